@@ -70,7 +70,12 @@ const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
 SELECT 
     feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id,
     feeds.name AS feed_name,
-    users.name AS user_name
+    users.name AS user_name,
+    (
+        SELECT COUNT(*) FROM posts
+        LEFT JOIN post_reads ON posts.id = post_reads.post_id AND post_reads.user_id = feed_follows.user_id
+        WHERE posts.feed_id = feed_follows.feed_id AND post_reads.post_id IS NULL
+    ) AS unread_count
 FROM feed_follows
 INNER JOIN users ON feed_follows.user_id = users.id
 INNER JOIN feeds ON feed_follows.feed_id = feeds.id
@@ -78,13 +83,14 @@ WHERE feed_follows.user_id = $1
 `
 
 type GetFeedFollowsForUserRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.UUID
-	FeedID    uuid.UUID
-	FeedName  string
-	UserName  sql.NullString
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uuid.UUID
+	FeedID      uuid.UUID
+	FeedName    string
+	UserName    sql.NullString
+	UnreadCount int64
 }
 
 func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
@@ -104,6 +110,53 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 			&i.FeedID,
 			&i.FeedName,
 			&i.UserName,
+			&i.UnreadCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFollowedFeeds = `-- name: GetFollowedFeeds :many
+SELECT feeds.id, feeds.created_at, feeds.updated_at, feeds.name, feeds.url, feeds.user_id, feeds.last_fetched_at, feeds.claimed_until, feeds.etag, feeds.last_modified, feeds.disabled_at, feeds.last_error, feeds.consecutive_failures, feeds.next_fetch_at, feeds.poll_interval_seconds FROM feeds
+INNER JOIN feed_follows ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1
+ORDER BY feeds.name
+`
+
+func (q *Queries) GetFollowedFeeds(ctx context.Context, userID uuid.UUID) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowedFeeds, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.ClaimedUntil,
+			&i.Etag,
+			&i.LastModified,
+			&i.DisabledAt,
+			&i.LastError,
+			&i.ConsecutiveFailures,
+			&i.NextFetchAt,
+			&i.PollIntervalSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -13,10 +13,125 @@ import (
 	"github.com/google/uuid"
 )
 
+const claimFeed = `-- name: ClaimFeed :one
+UPDATE feeds
+SET claimed_until = NOW() + ($1::int * INTERVAL '1 second')
+WHERE feeds.id = $2
+    AND (claimed_until IS NULL OR claimed_until < NOW())
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_until, etag, last_modified, disabled_at, last_error, consecutive_failures, next_fetch_at, poll_interval_seconds
+`
+
+type ClaimFeedParams struct {
+	LeaseSeconds int32
+	ID           uuid.UUID
+}
+
+func (q *Queries) ClaimFeed(ctx context.Context, arg ClaimFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, claimFeed, arg.LeaseSeconds, arg.ID)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ClaimedUntil,
+		&i.Etag,
+		&i.LastModified,
+		&i.DisabledAt,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.NextFetchAt,
+		&i.PollIntervalSeconds,
+	)
+	return i, err
+}
+
+const claimFeedsToFetch = `-- name: ClaimFeedsToFetch :many
+UPDATE feeds
+SET claimed_until = NOW() + ($1::int * INTERVAL '1 second')
+WHERE id IN (
+    SELECT id FROM feeds
+    WHERE disabled_at IS NULL
+        AND (claimed_until IS NULL OR claimed_until < NOW())
+        AND (next_fetch_at IS NULL OR next_fetch_at <= NOW())
+    ORDER BY next_fetch_at ASC NULLS FIRST
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_until, etag, last_modified, disabled_at, last_error, consecutive_failures, next_fetch_at, poll_interval_seconds
+`
+
+type ClaimFeedsToFetchParams struct {
+	LeaseSeconds int32
+	BatchSize    int32
+}
+
+func (q *Queries) ClaimFeedsToFetch(ctx context.Context, arg ClaimFeedsToFetchParams) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, claimFeedsToFetch, arg.LeaseSeconds, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.ClaimedUntil,
+			&i.Etag,
+			&i.LastModified,
+			&i.DisabledAt,
+			&i.LastError,
+			&i.ConsecutiveFailures,
+			&i.NextFetchAt,
+			&i.PollIntervalSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countDueFeeds = `-- name: CountDueFeeds :one
+SELECT
+    COUNT(*) FILTER (WHERE next_fetch_at IS NULL OR next_fetch_at <= NOW()) AS due,
+    COUNT(*) FILTER (WHERE next_fetch_at < NOW() - ($1::int * INTERVAL '1 second')) AS overdue
+FROM feeds
+WHERE disabled_at IS NULL
+`
+
+type CountDueFeedsRow struct {
+	Due     int64
+	Overdue int64
+}
+
+func (q *Queries) CountDueFeeds(ctx context.Context, overdueSeconds int32) (CountDueFeedsRow, error) {
+	row := q.db.QueryRowContext(ctx, countDueFeeds, overdueSeconds)
+	var i CountDueFeedsRow
+	err := row.Scan(&i.Due, &i.Overdue)
+	return i, err
+}
+
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
 VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_until, etag, last_modified, disabled_at, last_error, consecutive_failures, next_fetch_at, poll_interval_seconds
 `
 
 type CreateFeedParams struct {
@@ -46,12 +161,45 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.ClaimedUntil,
+		&i.Etag,
+		&i.LastModified,
+		&i.DisabledAt,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.NextFetchAt,
+		&i.PollIntervalSeconds,
 	)
 	return i, err
 }
 
+const disableFeed = `-- name: DisableFeed :exec
+UPDATE feeds
+SET disabled_at = NOW(), updated_at = NOW()
+WHERE feeds.id = $1
+`
+
+func (q *Queries) DisableFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, disableFeed, id)
+	return err
+}
+
+const enableFeed = `-- name: EnableFeed :execrows
+UPDATE feeds
+SET disabled_at = NULL, last_error = NULL, consecutive_failures = 0, next_fetch_at = NULL, updated_at = NOW()
+WHERE feeds.url = $1
+`
+
+func (q *Queries) EnableFeed(ctx context.Context, url sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enableFeed, url)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getFeed = `-- name: GetFeed :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at fROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_until, etag, last_modified, disabled_at, last_error, consecutive_failures, next_fetch_at, poll_interval_seconds fROM feeds
 WHERE feeds.url = $1
 `
 
@@ -66,29 +214,105 @@ func (q *Queries) GetFeed(ctx context.Context, url sql.NullString) (Feed, error)
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.ClaimedUntil,
+		&i.Etag,
+		&i.LastModified,
+		&i.DisabledAt,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.NextFetchAt,
+		&i.PollIntervalSeconds,
 	)
 	return i, err
 }
 
-const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
-ORDER BY last_fetched_at ASC NULLS FIRST 
-LIMIT 1
+const getFeedsByName = `-- name: GetFeedsByName :many
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_until, etag, last_modified, disabled_at, last_error, consecutive_failures, next_fetch_at, poll_interval_seconds FROM feeds
+WHERE feeds.name = $1
 `
 
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
-	var i Feed
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Url,
-		&i.UserID,
-		&i.LastFetchedAt,
-	)
-	return i, err
+func (q *Queries) GetFeedsByName(ctx context.Context, name string) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedsByName, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.ClaimedUntil,
+			&i.Etag,
+			&i.LastModified,
+			&i.DisabledAt,
+			&i.LastError,
+			&i.ConsecutiveFailures,
+			&i.NextFetchAt,
+			&i.PollIntervalSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBrokenFeeds = `-- name: ListBrokenFeeds :many
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_until, etag, last_modified, disabled_at, last_error, consecutive_failures, next_fetch_at, poll_interval_seconds FROM feeds
+WHERE disabled_at IS NOT NULL OR consecutive_failures > 0
+ORDER BY disabled_at DESC NULLS LAST, consecutive_failures DESC
+`
+
+func (q *Queries) ListBrokenFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, listBrokenFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.ClaimedUntil,
+			&i.Etag,
+			&i.LastModified,
+			&i.DisabledAt,
+			&i.LastError,
+			&i.ConsecutiveFailures,
+			&i.NextFetchAt,
+			&i.PollIntervalSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const listFeeds = `-- name: ListFeeds :many
@@ -127,11 +351,112 @@ func (q *Queries) ListFeeds(ctx context.Context) ([]ListFeedsRow, error) {
 
 const markFetched = `-- name: MarkFetched :exec
 UPDATE feeds
-SET last_fetched_at = NOW(), updated_at = NOW()
-WHERE feeds.id = $1
+SET last_fetched_at = NOW(), updated_at = NOW(),
+    etag = $1, last_modified = $2,
+    poll_interval_seconds = $3,
+    last_error = NULL, consecutive_failures = 0,
+    next_fetch_at = NOW() + ($4::int * INTERVAL '1 second')
+WHERE feeds.id = $5
+`
+
+type MarkFetchedParams struct {
+	Etag                sql.NullString
+	LastModified        sql.NullString
+	PollIntervalSeconds sql.NullInt32
+	NextFetchInSeconds  int32
+	ID                  uuid.UUID
+}
+
+func (q *Queries) MarkFetched(ctx context.Context, arg MarkFetchedParams) error {
+	_, err := q.db.ExecContext(ctx, markFetched,
+		arg.Etag,
+		arg.LastModified,
+		arg.PollIntervalSeconds,
+		arg.NextFetchInSeconds,
+		arg.ID,
+	)
+	return err
+}
+
+const recordFeedFailure = `-- name: RecordFeedFailure :one
+UPDATE feeds
+SET last_error = $1,
+    consecutive_failures = consecutive_failures + 1,
+    disabled_at = CASE
+        WHEN consecutive_failures + 1 >= $2::int THEN NOW()
+        ELSE disabled_at
+    END,
+    next_fetch_at = NOW() + ($3::int * INTERVAL '1 second'),
+    updated_at = NOW()
+WHERE feeds.id = $4
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, claimed_until, etag, last_modified, disabled_at, last_error, consecutive_failures, next_fetch_at, poll_interval_seconds
+`
+
+type RecordFeedFailureParams struct {
+	LastError      sql.NullString
+	MaxFailures    int32
+	RetryInSeconds int32
+	ID             uuid.UUID
+}
+
+func (q *Queries) RecordFeedFailure(ctx context.Context, arg RecordFeedFailureParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, recordFeedFailure,
+		arg.LastError,
+		arg.MaxFailures,
+		arg.RetryInSeconds,
+		arg.ID,
+	)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ClaimedUntil,
+		&i.Etag,
+		&i.LastModified,
+		&i.DisabledAt,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.NextFetchAt,
+		&i.PollIntervalSeconds,
+	)
+	return i, err
+}
+
+const releaseFeedClaim = `-- name: ReleaseFeedClaim :exec
+UPDATE feeds
+SET claimed_until = NULL
+WHERE feeds.id = $1 AND claimed_until = $2
 `
 
-func (q *Queries) MarkFetched(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, markFetched, id)
+type ReleaseFeedClaimParams struct {
+	ID           uuid.UUID
+	ClaimedUntil sql.NullTime
+}
+
+// only release the lease this claim took: once it expires another
+// process may have claimed the feed again
+func (q *Queries) ReleaseFeedClaim(ctx context.Context, arg ReleaseFeedClaimParams) error {
+	_, err := q.db.ExecContext(ctx, releaseFeedClaim, arg.ID, arg.ClaimedUntil)
+	return err
+}
+
+const scheduleFeed = `-- name: ScheduleFeed :exec
+UPDATE feeds
+SET next_fetch_at = NOW() + ($1::int * INTERVAL '1 second'), updated_at = NOW()
+WHERE feeds.id = $2
+`
+
+type ScheduleFeedParams struct {
+	RetryInSeconds int32
+	ID             uuid.UUID
+}
+
+func (q *Queries) ScheduleFeed(ctx context.Context, arg ScheduleFeedParams) error {
+	_, err := q.db.ExecContext(ctx, scheduleFeed, arg.RetryInSeconds, arg.ID)
 	return err
 }
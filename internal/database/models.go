@@ -12,13 +12,33 @@ import (
 )
 
 type Feed struct {
+	ID                  uuid.UUID
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	Name                string
+	Url                 sql.NullString
+	UserID              uuid.UUID
+	LastFetchedAt       sql.NullTime
+	ClaimedUntil        sql.NullTime
+	Etag                sql.NullString
+	LastModified        sql.NullString
+	DisabledAt          sql.NullTime
+	LastError           sql.NullString
+	ConsecutiveFailures int32
+	NextFetchAt         sql.NullTime
+	PollIntervalSeconds sql.NullInt32
+}
+
+type FeedFetch struct {
 	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           sql.NullString
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	FeedID        uuid.UUID
+	StartedAt     time.Time
+	DurationMs    int32
+	HttpStatus    sql.NullInt32
+	Bytes         sql.NullInt32
+	ItemsSeen     int32
+	PostsInserted int32
+	Error         sql.NullString
 }
 
 type FeedFollow struct {
@@ -38,6 +58,19 @@ type Post struct {
 	Description sql.NullString
 	PublishedAt time.Time
 	FeedID      uuid.UUID
+	Search      interface{}
+}
+
+type PostRead struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	ReadAt time.Time
+}
+
+type PostStar struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	StarredAt time.Time
 }
 
 type User struct {
@@ -31,7 +31,19 @@ type CreatePostParams struct {
 	FeedID      uuid.UUID
 }
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
+type CreatePostRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Url         string
+	Description sql.NullString
+	PublishedAt time.Time
+	FeedID      uuid.UUID
+}
+
+// posts queries list their columns to leave the search vector behind
+func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (CreatePostRow, error) {
 	row := q.db.QueryRowContext(ctx, createPost,
 		arg.ID,
 		arg.CreatedAt,
@@ -42,7 +54,39 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.PublishedAt,
 		arg.FeedID,
 	)
-	var i Post
+	var i CreatePostRow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+	)
+	return i, err
+}
+
+const getPost = `-- name: GetPost :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id FROM posts
+WHERE id = $1
+`
+
+type GetPostRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Url         string
+	Description sql.NullString
+	PublishedAt time.Time
+	FeedID      uuid.UUID
+}
+
+func (q *Queries) GetPost(ctx context.Context, id uuid.UUID) (GetPostRow, error) {
+	row := q.db.QueryRowContext(ctx, getPost, id)
+	var i GetPostRow
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
@@ -57,26 +101,66 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 }
 
 const getPosts = `-- name: GetPosts :many
-SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id FROM posts
-JOIN feeds ON posts.feed_id = feeds.id
-WHERE feeds.user_id = $1
-ORDER BY published_at DESC LIMIT $2
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, post_reads.read_at FROM posts
+JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+LEFT JOIN post_reads ON posts.id = post_reads.post_id AND post_reads.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+    AND ($2::uuid IS NULL OR posts.feed_id = $2)
+    AND ($3::timestamp IS NULL OR posts.published_at >= $3)
+    AND ($4::timestamp IS NULL OR posts.published_at < $4)
+    AND ($5::timestamp IS NULL
+        OR (posts.published_at, posts.id) < ($5, $6::uuid))
+    AND (NOT $7::boolean OR post_reads.read_at IS NULL)
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT $8 OFFSET $9
 `
 
 type GetPostsParams struct {
-	UserID uuid.UUID
-	Limit  int32
+	UserID            uuid.UUID
+	FeedID            uuid.NullUUID
+	Since             sql.NullTime
+	Until             sql.NullTime
+	BeforePublishedAt sql.NullTime
+	BeforeID          uuid.NullUUID
+	UnreadOnly        bool
+	PageSize          int32
+	PageOffset        int32
+}
+
+type GetPostsRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Url         string
+	Description sql.NullString
+	PublishedAt time.Time
+	FeedID      uuid.UUID
+	ReadAt      sql.NullTime
 }
 
-func (q *Queries) GetPosts(ctx context.Context, arg GetPostsParams) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, getPosts, arg.UserID, arg.Limit)
+// posts of the feeds the user follows, newest first, with when the user
+// read them. Every filter is optional, before_published_at and before_id
+// continue after a cursor
+func (q *Queries) GetPosts(ctx context.Context, arg GetPostsParams) ([]GetPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPosts,
+		arg.UserID,
+		arg.FeedID,
+		arg.Since,
+		arg.Until,
+		arg.BeforePublishedAt,
+		arg.BeforeID,
+		arg.UnreadOnly,
+		arg.PageSize,
+		arg.PageOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Post
+	var items []GetPostsRow
 	for rows.Next() {
-		var i Post
+		var i GetPostsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
@@ -86,6 +170,7 @@ func (q *Queries) GetPosts(ctx context.Context, arg GetPostsParams) ([]Post, err
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.ReadAt,
 		); err != nil {
 			return nil, err
 		}
@@ -99,3 +184,38 @@ func (q *Queries) GetPosts(ctx context.Context, arg GetPostsParams) ([]Post, err
 	}
 	return items, nil
 }
+
+const getRecentPublishDates = `-- name: GetRecentPublishDates :many
+SELECT published_at FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC
+LIMIT $2
+`
+
+type GetRecentPublishDatesParams struct {
+	FeedID uuid.UUID
+	Limit  int32
+}
+
+func (q *Queries) GetRecentPublishDates(ctx context.Context, arg GetRecentPublishDatesParams) ([]time.Time, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentPublishDates, arg.FeedID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []time.Time
+	for rows.Next() {
+		var published_at time.Time
+		if err := rows.Scan(&published_at); err != nil {
+			return nil, err
+		}
+		items = append(items, published_at)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,185 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/OriElbaz/gatorcli/internal/store"
+	"github.com/OriElbaz/gatorcli/sql/schema"
+	sqliteschema "github.com/OriElbaz/gatorcli/sql/sqlite/schema"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+)
+
+// ErrOutdated is returned by Check when migrations are waiting to be applied
+var ErrOutdated = errors.New("database schema is out of date")
+
+
+// Up applies every pending migration
+func Up(ctx context.Context, db *sql.DB, engine store.Engine) ([]*goose.MigrationResult, error) {
+	provider, err := newProvider(db, engine)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := provider.Up(ctx)
+	if err != nil {
+		return results, fmt.Errorf("migrate up: %w", err)
+	}
+
+	return results, nil
+}
+
+
+// Down rolls back the most recent migration
+func Down(ctx context.Context, db *sql.DB, engine store.Engine) (*goose.MigrationResult, error) {
+	provider, err := newProvider(db, engine)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := provider.Down(ctx)
+	if err != nil {
+		return result, fmt.Errorf("migrate down: %w", err)
+	}
+
+	return result, nil
+}
+
+
+// Status lists every embedded migration and whether it has been applied
+func Status(ctx context.Context, db *sql.DB, engine store.Engine) ([]*goose.MigrationStatus, error) {
+	provider, err := newProvider(db, engine)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := versionTableExists(ctx, db, engine)
+	if err != nil {
+		return nil, err
+	}
+
+	// goose can't read the status of a database it has never touched
+	if !exists {
+		var status []*goose.MigrationStatus
+		for _, source := range provider.ListSources() {
+			status = append(status, &goose.MigrationStatus{Source: source, State: goose.StatePending})
+		}
+		return status, nil
+	}
+
+	status, err := provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migration status: %w", err)
+	}
+
+	return status, nil
+}
+
+
+// Versions returns the schema version of the database, 0 if it was never
+// migrated, and the latest version embedded in this binary
+func Versions(ctx context.Context, db *sql.DB, engine store.Engine) (current int64, latest int64, err error) {
+	provider, err := newProvider(db, engine)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sources := provider.ListSources()
+	latest = sources[len(sources)-1].Version
+
+	exists, err := versionTableExists(ctx, db, engine)
+	if err != nil {
+		return 0, latest, err
+	}
+	if !exists {
+		return 0, latest, nil
+	}
+
+	current, err = provider.GetDBVersion(ctx)
+	if err != nil {
+		return 0, latest, fmt.Errorf("get schema version: %w", err)
+	}
+
+	return current, latest, nil
+}
+
+
+// Check fails with ErrOutdated when the database is behind this binary
+func Check(ctx context.Context, db *sql.DB, engine store.Engine) error {
+	current, latest, err := Versions(ctx, db, engine)
+	if err != nil {
+		return err
+	}
+
+	if current < latest {
+		return fmt.Errorf("%w: at version %d, gator needs %d, run: gator migrate up", ErrOutdated, current, latest)
+	}
+
+	return nil
+}
+
+
+/** HELPER FUNCTIONS **/
+
+func newProvider(db *sql.DB, engine store.Engine) (*goose.Provider, error) {
+	provider, err := goose.NewProvider(dialect(engine), db, migrations(engine))
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+
+	return provider, nil
+}
+
+
+func versionTableExists(ctx context.Context, db *sql.DB, engine store.Engine) (bool, error) {
+	// goose only knows how to look for its table in postgres
+	if engine == store.SQLite {
+		var tables int
+		err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", goose.DefaultTablename).Scan(&tables)
+		if err != nil {
+			return false, fmt.Errorf("check goose version table: %w", err)
+		}
+		return tables > 0, nil
+	}
+
+	versions, err := database.NewStore(dialect(engine), goose.DefaultTablename)
+	if err != nil {
+		return false, fmt.Errorf("new goose store: %w", err)
+	}
+
+	extended, ok := versions.(database.StoreExtender)
+	if !ok {
+		return false, fmt.Errorf("goose store can't check for its version table")
+	}
+
+	exists, err := extended.TableExists(ctx, db)
+	if err != nil {
+		return false, fmt.Errorf("check goose version table: %w", err)
+	}
+
+	return exists, nil
+}
+
+
+// each engine has its own migrations, sql/schema for postgres and
+// sql/sqlite/schema for SQLite
+func migrations(engine store.Engine) fs.FS {
+	if engine == store.SQLite {
+		return sqliteschema.FS
+	}
+
+	return schema.FS
+}
+
+
+func dialect(engine store.Engine) goose.Dialect {
+	if engine == store.SQLite {
+		return goose.DialectSQLite3
+	}
+
+	return goose.DialectPostgres
+}
@@ -0,0 +1,336 @@
+// Package search turns what users type into gator search into full-text
+// queries: Postgres tsquery syntax for the indexed search, plus plain word
+// matching and highlighting for databases without a full-text index.
+package search
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ErrNoTerms is returned for a query with nothing to look for, such as
+// only punctuation or only excluded words
+var ErrNoTerms = errors.New("search needs at least one word to match")
+
+// Highlight marks are put around every matched word
+const (
+	HighlightStart = "**"
+	HighlightStop  = "**"
+)
+
+// snippetWords is roughly how many words a description snippet keeps,
+// like ts_headline's MaxWords
+const snippetWords = 30
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+
+/***** STRUCTS *****/
+
+// Query is a parsed search: every clause must match, and a clause matches
+// when any of its terms does
+type Query struct {
+	clauses [][]term
+}
+
+
+// term is a word, or a phrase of words that must appear in order
+type term struct {
+	words  []string
+	prefix bool // the last word only has to start the text's word
+	negate bool
+}
+
+
+/** PARSING **/
+
+// Parse reads a query made of words, which must all match, "quoted
+// phrases", prefix* words, OR between alternatives and -excluded words or
+// phrases
+func Parse(input string) (Query, error) {
+	var query Query
+	var or bool
+
+	for rest := strings.TrimSpace(input); rest != ""; rest = strings.TrimSpace(rest) {
+		var t term
+		if rest[0] == '-' && len(rest) > 1 && !unicode.IsSpace(rune(rest[1])) {
+			t.negate = true
+			rest = rest[1:]
+		}
+
+		var token string
+		if rest[0] == '"' {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				token, rest = rest[1:], ""
+			} else {
+				token, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(rest, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+			if end < 0 {
+				end = len(rest)
+			}
+			token, rest = rest[:end], rest[end:]
+
+			if token == "OR" && !t.negate {
+				or = len(query.clauses) > 0
+				continue
+			}
+		}
+
+		t.prefix = strings.HasSuffix(token, "*")
+		t.words = words(token)
+		if len(t.words) == 0 {
+			continue
+		}
+
+		// excluded terms never join an OR group, "go OR -java" would match
+		// nearly every post, so they narrow the query like anywhere else
+		last := len(query.clauses) - 1
+		if or && !t.negate && allPositive(query.clauses[last]) {
+			query.clauses[last] = append(query.clauses[last], t)
+		} else {
+			query.clauses = append(query.clauses, []term{t})
+		}
+		or = false
+	}
+
+	// excluding words only narrows down what something else matched
+	for _, clause := range query.clauses {
+		if allPositive(clause) {
+			return query, nil
+		}
+	}
+
+	return Query{}, ErrNoTerms
+}
+
+
+/** POSTGRES **/
+
+// TSQuery renders the query for to_tsquery, words are letters and digits
+// only so they never need escaping
+func (q Query) TSQuery() string {
+	clauses := make([]string, len(q.clauses))
+	for i, clause := range q.clauses {
+		terms := make([]string, len(clause))
+		for j, t := range clause {
+			terms[j] = t.tsquery()
+		}
+
+		clauses[i] = strings.Join(terms, " | ")
+		if len(terms) > 1 {
+			clauses[i] = "(" + clauses[i] + ")"
+		}
+	}
+
+	return strings.Join(clauses, " & ")
+}
+
+
+func (t term) tsquery() string {
+	lexemes := make([]string, len(t.words))
+	for i, word := range t.words {
+		lexemes[i] = "'" + word + "'"
+	}
+	if t.prefix {
+		lexemes[len(lexemes)-1] += ":*"
+	}
+
+	rendered := strings.Join(lexemes, " <-> ")
+	if len(lexemes) > 1 {
+		rendered = "(" + rendered + ")"
+	}
+	if t.negate {
+		rendered = "!" + rendered
+	}
+
+	return rendered
+}
+
+
+/** PLAIN MATCHING **/
+
+// Rank scores a post by comparing whole words, without stemming: 0 when it
+// doesn't match, otherwise two points for every term found in the title
+// and one for every term found in the description
+func (q Query) Rank(title string, description string) float32 {
+	titleWords := words(title)
+	descriptionWords := words(StripHTML(description))
+
+	var rank float32
+	for _, clause := range q.clauses {
+		matched := false
+
+		for _, t := range clause {
+			inTitle := len(t.find(titleWords)) > 0
+			inDescription := len(t.find(descriptionWords)) > 0
+			found := inTitle || inDescription
+
+			if found != t.negate {
+				matched = true
+			}
+			if t.negate {
+				continue
+			}
+			if inTitle {
+				rank += 2
+			}
+			if inDescription {
+				rank++
+			}
+		}
+
+		if !matched {
+			return 0
+		}
+	}
+
+	return rank
+}
+
+
+// Highlight marks the words of text that the query matches. A snippet is
+// cut down to the words around the first match
+func (q Query) Highlight(text string, snippet bool) string {
+	text = strings.Join(strings.Fields(text), " ")
+	spans := wordSpans(text)
+
+	lowered := make([]string, len(spans))
+	for i, span := range spans {
+		lowered[i] = strings.ToLower(text[span[0]:span[1]])
+	}
+
+	marked := make([]bool, len(spans))
+	first := -1
+	for _, clause := range q.clauses {
+		for _, t := range clause {
+			if t.negate {
+				continue
+			}
+			for _, start := range t.find(lowered) {
+				for i := start; i < start+len(t.words); i++ {
+					marked[i] = true
+				}
+				if first < 0 || start < first {
+					first = start
+				}
+			}
+		}
+	}
+
+	from, to := 0, len(spans)
+	if snippet && len(spans) > snippetWords {
+		from = max(0, first-snippetWords/3)
+		to = min(len(spans), from+snippetWords)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("...")
+	}
+
+	offset := 0
+	if from > 0 {
+		offset = spans[from][0]
+	}
+	end := len(text)
+	if to < len(spans) {
+		end = spans[to-1][1]
+	}
+
+	for i := from; i < to; i++ {
+		if !marked[i] {
+			continue
+		}
+		b.WriteString(text[offset:spans[i][0]])
+		b.WriteString(HighlightStart + text[spans[i][0]:spans[i][1]] + HighlightStop)
+		offset = spans[i][1]
+	}
+	b.WriteString(text[offset:end])
+
+	if end < len(text) {
+		b.WriteString("...")
+	}
+
+	return b.String()
+}
+
+
+// StripHTML drops the tags descriptions often carry, leaving their text
+func StripHTML(text string) string {
+	return htmlTag.ReplaceAllString(text, " ")
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// find returns where in words the term starts, a negated term is looked
+// up like any other
+func (t term) find(words []string) []int {
+	var starts []int
+
+	for start := 0; start+len(t.words) <= len(words); start++ {
+		matches := true
+		for i, word := range t.words {
+			isLast := i == len(t.words)-1
+			if words[start+i] != word && !(isLast && t.prefix && strings.HasPrefix(words[start+i], word)) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			starts = append(starts, start)
+		}
+	}
+
+	return starts
+}
+
+
+func allPositive(clause []term) bool {
+	for _, t := range clause {
+		if t.negate {
+			return false
+		}
+	}
+
+	return true
+}
+
+
+// words splits text into lower case runs of letters and digits
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), isWordBreak)
+}
+
+
+// wordSpans returns the byte offsets of every word of text
+func wordSpans(text string) [][2]int {
+	var spans [][2]int
+
+	start := -1
+	for i, r := range text {
+		switch {
+		case isWordBreak(r) && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		case !isWordBreak(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+
+	return spans
+}
+
+
+func isWordBreak(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
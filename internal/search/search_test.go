@@ -0,0 +1,133 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		isErr    bool
+	}{
+		{name: "Words", input: "go generics", expected: "'go' & 'generics'"},
+		{name: "Phrase", input: `"type parameters" go`, expected: "('type' <-> 'parameters') & 'go'"},
+		{name: "Prefix", input: "generic*", expected: "'generic':*"},
+		{name: "Prefix Phrase", input: `"go gen*"`, expected: "('go' <-> 'gen':*)"},
+		{name: "Or", input: "rust OR go OR zig release", expected: "('rust' | 'go' | 'zig') & 'release'"},
+		{name: "Exclude", input: "go -java -\"spring boot\"", expected: "'go' & !'java' & !('spring' <-> 'boot')"},
+		{name: "Punctuation", input: "c++ it's (fast)", expected: "'c' & ('it' <-> 's') & 'fast'"},
+		{name: "Unclosed Quote", input: `"release notes`, expected: "('release' <-> 'notes')"},
+		{name: "Leading Or", input: "OR go", expected: "'go'"},
+		{name: "Excluded After Or", input: "go OR -java", expected: "'go' & !'java'"},
+		{name: "Or After Excluded", input: "-java OR go", expected: "!'java' & 'go'"},
+		{name: "Lowercase Or Is A Word", input: "this or that", expected: "'this' & 'or' & 'that'"},
+		{name: "Only Excluded", input: "-java", isErr: true},
+		{name: "Only Punctuation", input: "!!! ---", isErr: true},
+		{name: "Empty", input: "   ", isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := Parse(tc.input)
+			if tc.isErr {
+				if !errors.Is(err, ErrNoTerms) {
+					t.Fatalf("expected ErrNoTerms for %q, got %v", tc.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := query.TSQuery(); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestRank(t *testing.T) {
+	const title = "Go 1.26 release notes"
+	const description = "<p>Generic type aliases and a faster <b>garbage collector</b>.</p>"
+
+	tests := []struct {
+		name     string
+		query    string
+		expected float32
+	}{
+		{name: "Title Match", query: "release", expected: 2},
+		{name: "Description Match", query: "garbage", expected: 1},
+		{name: "Both", query: "release garbage", expected: 3},
+		{name: "Prefix", query: "gener*", expected: 1},
+		{name: "Phrase", query: `"garbage collector"`, expected: 1},
+		{name: "Phrase Out Of Order", query: `"collector garbage"`, expected: 0},
+		{name: "Missing Word", query: "release rust", expected: 0},
+		{name: "Or", query: "rust OR release", expected: 2},
+		{name: "Excluded After Or", query: "release OR -garbage", expected: 0},
+		{name: "Excluded After Or Missing", query: "release OR -rust", expected: 2},
+		{name: "Excluded", query: "release -garbage", expected: 0},
+		{name: "Excluded Missing", query: "release -rust", expected: 2},
+		{name: "No Stemming", query: "releases", expected: 0},
+		{name: "Tags Are Not Words", query: "release p", expected: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := Parse(tc.query)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tc.query, err)
+			}
+
+			if got := query.Rank(title, description); got != tc.expected {
+				t.Errorf("expected rank %v, got %v", tc.expected, got)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		text     string
+		snippet  bool
+		expected string
+	}{
+		{name: "Words", query: "go release", text: "Go 1.26 release notes", expected: "**Go** 1.26 **release** notes"},
+		{name: "Phrase", query: `"release notes"`, text: "Release notes: release day", expected: "**Release** **notes**: release day"},
+		{name: "Prefix", query: "gen*", text: "Generic generators", expected: "**Generic** **generators**"},
+		{name: "Excluded Not Marked", query: "go -notes", text: "Go notes", expected: "**Go** notes"},
+		{name: "Whitespace", query: "go", text: "go\n\n  fast", expected: "**go** fast"},
+		{
+			name:     "Snippet Around Match",
+			query:    "needle",
+			text:     "w1 w2 w3 w4 w5 w6 w7 w8 w9 w10 w11 w12 w13 w14 w15 needle w17 w18 w19 w20 w21 w22 w23 w24 w25 w26 w27 w28 w29 w30 w31 w32 w33 w34 w35 w36 w37 w38 w39 w40",
+			snippet:  true,
+			expected: "...w6 w7 w8 w9 w10 w11 w12 w13 w14 w15 **needle** w17 w18 w19 w20 w21 w22 w23 w24 w25 w26 w27 w28 w29 w30 w31 w32 w33 w34 w35...",
+		},
+		{name: "Short Snippet", query: "go", text: "go fast", snippet: true, expected: "**go** fast"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := Parse(tc.query)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tc.query, err)
+			}
+
+			if got := query.Highlight(tc.text, tc.snippet); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
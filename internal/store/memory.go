@@ -0,0 +1,928 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+// ErrConstraint is returned by Memory where the database would report a
+// unique or foreign key violation
+var ErrConstraint = errors.New("constraint violation")
+
+
+// Memory is a Store that keeps everything in maps, for tests. It follows
+// the SQL queries' semantics, sql.ErrNoRows included, but its
+// transactions only roll back, they are not isolated.
+type Memory struct {
+	mu   sync.Mutex
+	data memoryData
+}
+
+
+type memoryData struct {
+	users   map[uuid.UUID]database.User
+	feeds   map[uuid.UUID]database.Feed
+	follows map[uuid.UUID]database.FeedFollow
+	posts   map[uuid.UUID]database.Post
+	reads   map[readKey]database.PostRead
+	stars   map[readKey]database.PostStar
+	fetches map[uuid.UUID]database.FeedFetch
+}
+
+
+// readKey identifies a user's read or star of a post
+type readKey struct {
+	userID uuid.UUID
+	postID uuid.UUID
+}
+
+
+var _ Store = (*Memory)(nil)
+
+
+func NewMemory() *Memory {
+	return &Memory{
+		data: memoryData{
+			users:   map[uuid.UUID]database.User{},
+			feeds:   map[uuid.UUID]database.Feed{},
+			follows: map[uuid.UUID]database.FeedFollow{},
+			posts:   map[uuid.UUID]database.Post{},
+			reads:   map[readKey]database.PostRead{},
+			stars:   map[readKey]database.PostStar{},
+			fetches: map[uuid.UUID]database.FeedFetch{},
+		},
+	}
+}
+
+
+/** TRANSACTIONS **/
+
+func (m *Memory) InTx(ctx context.Context, fn func(Store) error) error {
+	return m.rollbackOnError(fn)
+}
+
+
+func (m *Memory) Savepoint(ctx context.Context, fn func() error) error {
+	return m.rollbackOnError(func(Store) error { return fn() })
+}
+
+
+func (m *Memory) rollbackOnError(fn func(Store) error) error {
+	m.mu.Lock()
+	snapshot := m.data.clone()
+	m.mu.Unlock()
+
+	if err := fn(m); err != nil {
+		m.mu.Lock()
+		m.data = snapshot
+		m.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+
+func (d memoryData) clone() memoryData {
+	return memoryData{
+		users:   maps.Clone(d.users),
+		feeds:   maps.Clone(d.feeds),
+		follows: maps.Clone(d.follows),
+		posts:   maps.Clone(d.posts),
+		reads:   maps.Clone(d.reads),
+		stars:   maps.Clone(d.stars),
+		fetches: maps.Clone(d.fetches),
+	}
+}
+
+
+/** USERS **/
+
+func (m *Memory) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, user := range m.data.users {
+		if user.ID == arg.ID || (arg.Name.Valid && user.Name == arg.Name) {
+			return database.User{}, ErrConstraint
+		}
+	}
+
+	user := database.User(arg)
+	m.data.users[user.ID] = user
+
+	return user, nil
+}
+
+
+func (m *Memory) GetUser(ctx context.Context, name sql.NullString) (database.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, user := range m.data.users {
+		if name.Valid && user.Name == name {
+			return user, nil
+		}
+	}
+
+	return database.User{}, sql.ErrNoRows
+}
+
+
+func (m *Memory) GetUsers(ctx context.Context) ([]sql.NullString, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var names []sql.NullString
+	for _, user := range sortedBy(m.data.users, func(u database.User) time.Time { return u.CreatedAt }) {
+		names = append(names, user.Name)
+	}
+
+	return names, nil
+}
+
+
+// ClearTableUsers cascades to everything the users own, like the foreign
+// keys do
+func (m *Memory) ClearTableUsers(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.data.users)
+	clear(m.data.feeds)
+	clear(m.data.follows)
+	clear(m.data.posts)
+	clear(m.data.reads)
+	clear(m.data.stars)
+	clear(m.data.fetches)
+
+	return nil
+}
+
+
+/** FEEDS **/
+
+func (m *Memory) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.users[arg.UserID]; !ok {
+		return database.Feed{}, ErrConstraint
+	}
+
+	for _, feed := range m.data.feeds {
+		if feed.ID == arg.ID || (arg.Url.Valid && feed.Url == arg.Url) {
+			return database.Feed{}, ErrConstraint
+		}
+	}
+
+	feed := database.Feed{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		Name:      arg.Name,
+		Url:       arg.Url,
+		UserID:    arg.UserID,
+	}
+	m.data.feeds[feed.ID] = feed
+
+	return feed, nil
+}
+
+
+func (m *Memory) GetFeed(ctx context.Context, url sql.NullString) (database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, feed := range m.data.feeds {
+		if url.Valid && feed.Url == url {
+			return feed, nil
+		}
+	}
+
+	return database.Feed{}, sql.ErrNoRows
+}
+
+
+func (m *Memory) GetFeedsByName(ctx context.Context, name string) ([]database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.filterFeeds(func(feed database.Feed) bool { return feed.Name == name }), nil
+}
+
+
+func (m *Memory) ListFeeds(ctx context.Context) ([]database.ListFeedsRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var rows []database.ListFeedsRow
+	for _, feed := range m.filterFeeds(func(database.Feed) bool { return true }) {
+		rows = append(rows, database.ListFeedsRow{
+			Name:     feed.Name,
+			Url:      feed.Url,
+			UserName: m.data.users[feed.UserID].Name,
+		})
+	}
+
+	return rows, nil
+}
+
+
+func (m *Memory) ListBrokenFeeds(ctx context.Context) ([]database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	feeds := m.filterFeeds(func(feed database.Feed) bool {
+		return feed.DisabledAt.Valid || feed.ConsecutiveFailures > 0
+	})
+
+	slices.SortStableFunc(feeds, func(a, b database.Feed) int {
+		return cmp.Or(
+			-compareNullTime(a.DisabledAt, b.DisabledAt, false),
+			cmp.Compare(b.ConsecutiveFailures, a.ConsecutiveFailures),
+		)
+	})
+
+	return feeds, nil
+}
+
+
+func (m *Memory) ClaimFeed(ctx context.Context, arg database.ClaimFeedParams) (database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	feed, ok := m.data.feeds[arg.ID]
+	if !ok || (feed.ClaimedUntil.Valid && !feed.ClaimedUntil.Time.Before(now)) {
+		return database.Feed{}, sql.ErrNoRows
+	}
+
+	feed.ClaimedUntil = validTime(now.Add(seconds(arg.LeaseSeconds)))
+	m.data.feeds[feed.ID] = feed
+
+	return feed, nil
+}
+
+
+func (m *Memory) ClaimFeedsToFetch(ctx context.Context, arg database.ClaimFeedsToFetchParams) ([]database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	due := m.filterFeeds(func(feed database.Feed) bool {
+		return !feed.DisabledAt.Valid &&
+			(!feed.ClaimedUntil.Valid || feed.ClaimedUntil.Time.Before(now)) &&
+			(!feed.NextFetchAt.Valid || !feed.NextFetchAt.Time.After(now))
+	})
+
+	slices.SortStableFunc(due, func(a, b database.Feed) int {
+		return compareNullTime(a.NextFetchAt, b.NextFetchAt, true)
+	})
+
+	var claimed []database.Feed
+	for _, feed := range due[:min(len(due), int(arg.BatchSize))] {
+		feed.ClaimedUntil = validTime(now.Add(seconds(arg.LeaseSeconds)))
+		m.data.feeds[feed.ID] = feed
+		claimed = append(claimed, feed)
+	}
+
+	return claimed, nil
+}
+
+
+func (m *Memory) ReleaseFeedClaim(ctx context.Context, arg database.ReleaseFeedClaimParams) error {
+	m.updateFeed(arg.ID, func(feed *database.Feed) {
+		if feed.ClaimedUntil.Valid && arg.ClaimedUntil.Valid && feed.ClaimedUntil.Time.Equal(arg.ClaimedUntil.Time) {
+			feed.ClaimedUntil = sql.NullTime{}
+		}
+	})
+
+	return nil
+}
+
+
+func (m *Memory) CountDueFeeds(ctx context.Context, overdueSeconds int32) (database.CountDueFeedsRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	overdueBefore := now.Add(-seconds(overdueSeconds))
+
+	var counts database.CountDueFeedsRow
+	for _, feed := range m.data.feeds {
+		if feed.DisabledAt.Valid {
+			continue
+		}
+		if !feed.NextFetchAt.Valid || !feed.NextFetchAt.Time.After(now) {
+			counts.Due++
+		}
+		if feed.NextFetchAt.Valid && feed.NextFetchAt.Time.Before(overdueBefore) {
+			counts.Overdue++
+		}
+	}
+
+	return counts, nil
+}
+
+
+func (m *Memory) MarkFetched(ctx context.Context, arg database.MarkFetchedParams) error {
+	now := time.Now()
+
+	m.updateFeed(arg.ID, func(feed *database.Feed) {
+		feed.LastFetchedAt = validTime(now)
+		feed.UpdatedAt = now
+		feed.Etag = arg.Etag
+		feed.LastModified = arg.LastModified
+		feed.PollIntervalSeconds = arg.PollIntervalSeconds
+		feed.LastError = sql.NullString{}
+		feed.ConsecutiveFailures = 0
+		feed.NextFetchAt = validTime(now.Add(seconds(arg.NextFetchInSeconds)))
+	})
+
+	return nil
+}
+
+
+func (m *Memory) ScheduleFeed(ctx context.Context, arg database.ScheduleFeedParams) error {
+	now := time.Now()
+
+	m.updateFeed(arg.ID, func(feed *database.Feed) {
+		feed.NextFetchAt = validTime(now.Add(seconds(arg.RetryInSeconds)))
+		feed.UpdatedAt = now
+	})
+
+	return nil
+}
+
+
+func (m *Memory) RecordFeedFailure(ctx context.Context, arg database.RecordFeedFailureParams) (database.Feed, error) {
+	now := time.Now()
+
+	feed, ok := m.updateFeed(arg.ID, func(feed *database.Feed) {
+		feed.LastError = arg.LastError
+		feed.ConsecutiveFailures++
+		if feed.ConsecutiveFailures >= arg.MaxFailures {
+			feed.DisabledAt = validTime(now)
+		}
+		feed.NextFetchAt = validTime(now.Add(seconds(arg.RetryInSeconds)))
+		feed.UpdatedAt = now
+	})
+	if !ok {
+		return database.Feed{}, sql.ErrNoRows
+	}
+
+	return feed, nil
+}
+
+
+func (m *Memory) DisableFeed(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+
+	m.updateFeed(id, func(feed *database.Feed) {
+		feed.DisabledAt = validTime(now)
+		feed.UpdatedAt = now
+	})
+
+	return nil
+}
+
+
+func (m *Memory) EnableFeed(ctx context.Context, url sql.NullString) (int64, error) {
+	feed, err := m.GetFeed(ctx, url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	now := time.Now()
+
+	m.updateFeed(feed.ID, func(feed *database.Feed) {
+		feed.DisabledAt = sql.NullTime{}
+		feed.LastError = sql.NullString{}
+		feed.ConsecutiveFailures = 0
+		feed.NextFetchAt = sql.NullTime{}
+		feed.UpdatedAt = now
+	})
+
+	return 1, nil
+}
+
+
+/** FOLLOWS **/
+
+func (m *Memory) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, userExists := m.data.users[arg.UserID]
+	feed, feedExists := m.data.feeds[arg.FeedID]
+	if !userExists || !feedExists {
+		return database.CreateFeedFollowRow{}, ErrConstraint
+	}
+
+	for _, follow := range m.data.follows {
+		if follow.ID == arg.ID || (follow.UserID == arg.UserID && follow.FeedID == arg.FeedID) {
+			return database.CreateFeedFollowRow{}, ErrConstraint
+		}
+	}
+
+	follow := database.FeedFollow(arg)
+	m.data.follows[follow.ID] = follow
+
+	return database.CreateFeedFollowRow{
+		ID:        follow.ID,
+		CreatedAt: follow.CreatedAt,
+		UpdatedAt: follow.UpdatedAt,
+		UserID:    follow.UserID,
+		FeedID:    follow.FeedID,
+		FeedName:  feed.Name,
+		UserName:  user.Name,
+	}, nil
+}
+
+
+func (m *Memory) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsForUserRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var rows []database.GetFeedFollowsForUserRow
+	for _, follow := range sortedBy(m.data.follows, func(f database.FeedFollow) time.Time { return f.CreatedAt }) {
+		if follow.UserID != userID {
+			continue
+		}
+
+		rows = append(rows, database.GetFeedFollowsForUserRow{
+			ID:          follow.ID,
+			CreatedAt:   follow.CreatedAt,
+			UpdatedAt:   follow.UpdatedAt,
+			UserID:      follow.UserID,
+			FeedID:      follow.FeedID,
+			FeedName:    m.data.feeds[follow.FeedID].Name,
+			UserName:    m.data.users[follow.UserID].Name,
+			UnreadCount: m.unreadCount(follow.UserID, follow.FeedID),
+		})
+	}
+
+	return rows, nil
+}
+
+
+func (m *Memory) GetFollowedFeeds(ctx context.Context, userID uuid.UUID) ([]database.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	feeds := m.filterFeeds(func(feed database.Feed) bool { return m.follows(userID, feed.ID) })
+	slices.SortStableFunc(feeds, func(a, b database.Feed) int { return cmp.Compare(a.Name, b.Name) })
+
+	return feeds, nil
+}
+
+
+func (m *Memory) UnfollowFeed(ctx context.Context, arg database.UnfollowFeedParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, follow := range m.data.follows {
+		if follow.UserID == arg.UserID && follow.FeedID == arg.FeedID {
+			delete(m.data.follows, id)
+		}
+	}
+
+	return nil
+}
+
+
+/** POSTS **/
+
+// CreatePost returns sql.ErrNoRows for a url it already has, like
+// ON CONFLICT DO NOTHING
+func (m *Memory) CreatePost(ctx context.Context, arg database.CreatePostParams) (database.CreatePostRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.feeds[arg.FeedID]; !ok {
+		return database.CreatePostRow{}, ErrConstraint
+	}
+
+	for _, post := range m.data.posts {
+		if post.Url == arg.Url {
+			return database.CreatePostRow{}, sql.ErrNoRows
+		}
+		if post.ID == arg.ID {
+			return database.CreatePostRow{}, ErrConstraint
+		}
+	}
+
+	m.data.posts[arg.ID] = database.Post{
+		ID:          arg.ID,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+		Title:       arg.Title,
+		Url:         arg.Url,
+		Description: arg.Description,
+		PublishedAt: arg.PublishedAt,
+		FeedID:      arg.FeedID,
+	}
+
+	return database.CreatePostRow(arg), nil
+}
+
+
+func (m *Memory) GetPost(ctx context.Context, id uuid.UUID) (database.GetPostRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	post, ok := m.data.posts[id]
+	if !ok {
+		return database.GetPostRow{}, sql.ErrNoRows
+	}
+
+	return database.GetPostRow{
+		ID:          post.ID,
+		CreatedAt:   post.CreatedAt,
+		UpdatedAt:   post.UpdatedAt,
+		Title:       post.Title,
+		Url:         post.Url,
+		Description: post.Description,
+		PublishedAt: post.PublishedAt,
+		FeedID:      post.FeedID,
+	}, nil
+}
+
+
+// GetPosts matches the query: posts of the feeds the user follows, newest
+// first, after the optional filters and cursor
+func (m *Memory) GetPosts(ctx context.Context, arg database.GetPostsParams) ([]database.GetPostsRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var rows []database.GetPostsRow
+	for _, post := range m.newestPosts() {
+		read, isRead := m.data.reads[readKey{arg.UserID, post.ID}]
+
+		switch {
+		case !m.follows(arg.UserID, post.FeedID):
+		case arg.FeedID.Valid && post.FeedID != arg.FeedID.UUID:
+		case arg.Since.Valid && post.PublishedAt.Before(arg.Since.Time):
+		case arg.Until.Valid && !post.PublishedAt.Before(arg.Until.Time):
+		case arg.BeforePublishedAt.Valid && !postBefore(post, arg.BeforePublishedAt.Time, arg.BeforeID.UUID):
+		case arg.UnreadOnly && isRead:
+		default:
+			row := database.GetPostsRow{
+				ID:          post.ID,
+				CreatedAt:   post.CreatedAt,
+				UpdatedAt:   post.UpdatedAt,
+				Title:       post.Title,
+				Url:         post.Url,
+				Description: post.Description,
+				PublishedAt: post.PublishedAt,
+				FeedID:      post.FeedID,
+			}
+			if isRead {
+				row.ReadAt = validTime(read.ReadAt)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	offset := min(len(rows), int(arg.PageOffset))
+	rows = rows[offset:]
+
+	return rows[:min(len(rows), int(arg.PageSize))], nil
+}
+
+
+func (m *Memory) GetRecentPublishDates(ctx context.Context, arg database.GetRecentPublishDatesParams) ([]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var dates []time.Time
+	for _, post := range m.newestPosts() {
+		if post.FeedID == arg.FeedID {
+			dates = append(dates, post.PublishedAt)
+		}
+	}
+
+	return dates[:min(len(dates), int(arg.Limit))], nil
+}
+
+
+/** POST READS **/
+
+func (m *Memory) MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, userExists := m.data.users[arg.UserID]
+	_, postExists := m.data.posts[arg.PostID]
+	if !userExists || !postExists {
+		return ErrConstraint
+	}
+
+	key := readKey{arg.UserID, arg.PostID}
+	if _, ok := m.data.reads[key]; !ok {
+		m.data.reads[key] = database.PostRead(arg)
+	}
+
+	return nil
+}
+
+
+func (m *Memory) MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := readKey{arg.UserID, arg.PostID}
+	if _, ok := m.data.reads[key]; !ok {
+		return 0, nil
+	}
+
+	delete(m.data.reads, key)
+
+	return 1, nil
+}
+
+
+func (m *Memory) MarkPostsRead(ctx context.Context, arg database.MarkPostsReadParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var marked int64
+	for _, post := range m.data.posts {
+		key := readKey{arg.UserID, post.ID}
+
+		switch _, isRead := m.data.reads[key]; {
+		case isRead:
+		case !m.follows(arg.UserID, post.FeedID):
+		case arg.FeedID.Valid && post.FeedID != arg.FeedID.UUID:
+		default:
+			m.data.reads[key] = database.PostRead{UserID: arg.UserID, PostID: post.ID, ReadAt: arg.ReadAt}
+			marked++
+		}
+	}
+
+	return marked, nil
+}
+
+
+/** SEARCH **/
+
+// Search compares words post by post, there is no index to ask
+func (m *Memory) Search(ctx context.Context, arg SearchParams) ([]database.SearchPostsRow, error) {
+	return scanSearch(ctx, m, arg)
+}
+
+
+func (m *Memory) SearchPosts(ctx context.Context, arg database.SearchPostsParams) ([]database.SearchPostsRow, error) {
+	return nil, ErrNoFullTextSearch
+}
+
+
+/** POST STARS **/
+
+func (m *Memory) StarPost(ctx context.Context, arg database.StarPostParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, userExists := m.data.users[arg.UserID]
+	_, postExists := m.data.posts[arg.PostID]
+	if !userExists || !postExists {
+		return ErrConstraint
+	}
+
+	key := readKey{arg.UserID, arg.PostID}
+	if _, ok := m.data.stars[key]; !ok {
+		m.data.stars[key] = database.PostStar(arg)
+	}
+
+	return nil
+}
+
+
+func (m *Memory) UnstarPost(ctx context.Context, arg database.UnstarPostParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := readKey{arg.UserID, arg.PostID}
+	if _, ok := m.data.stars[key]; !ok {
+		return 0, nil
+	}
+
+	delete(m.data.stars, key)
+
+	return 1, nil
+}
+
+
+func (m *Memory) GetStarredPosts(ctx context.Context, userID uuid.UUID) ([]database.GetStarredPostsRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var rows []database.GetStarredPostsRow
+	for key, star := range m.data.stars {
+		if key.userID != userID {
+			continue
+		}
+
+		post := m.data.posts[key.postID]
+		rows = append(rows, database.GetStarredPostsRow{
+			ID:          post.ID,
+			CreatedAt:   post.CreatedAt,
+			UpdatedAt:   post.UpdatedAt,
+			Title:       post.Title,
+			Url:         post.Url,
+			Description: post.Description,
+			PublishedAt: post.PublishedAt,
+			FeedID:      post.FeedID,
+			FeedName:    m.data.feeds[post.FeedID].Name,
+			StarredAt:   star.StarredAt,
+		})
+	}
+
+	slices.SortStableFunc(rows, func(a, b database.GetStarredPostsRow) int {
+		return cmp.Or(b.StarredAt.Compare(a.StarredAt), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	return rows, nil
+}
+
+
+/** FEED FETCHES **/
+
+func (m *Memory) CreateFeedFetch(ctx context.Context, arg database.CreateFeedFetchParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.feeds[arg.FeedID]; !ok {
+		return ErrConstraint
+	}
+
+	m.data.fetches[arg.ID] = database.FeedFetch(arg)
+
+	return nil
+}
+
+
+func (m *Memory) GetFeedFetches(ctx context.Context, arg database.GetFeedFetchesParams) ([]database.FeedFetch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var fetches []database.FeedFetch
+	for _, fetch := range sortedBy(m.data.fetches, func(f database.FeedFetch) time.Time { return f.StartedAt }) {
+		if fetch.FeedID == arg.FeedID {
+			fetches = append(fetches, fetch)
+		}
+	}
+	slices.Reverse(fetches)
+
+	return fetches[:min(len(fetches), int(arg.Limit))], nil
+}
+
+
+func (m *Memory) PruneFeedFetches(ctx context.Context, startedAt time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pruned int64
+	for id, fetch := range m.data.fetches {
+		if fetch.StartedAt.Before(startedAt) {
+			delete(m.data.fetches, id)
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// filterFeeds returns the matching feeds oldest first, callers hold m.mu
+func (m *Memory) filterFeeds(keep func(database.Feed) bool) []database.Feed {
+	var feeds []database.Feed
+	for _, feed := range sortedBy(m.data.feeds, func(f database.Feed) time.Time { return f.CreatedAt }) {
+		if keep(feed) {
+			feeds = append(feeds, feed)
+		}
+	}
+
+	return feeds
+}
+
+
+func (m *Memory) updateFeed(id uuid.UUID, update func(*database.Feed)) (database.Feed, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	feed, ok := m.data.feeds[id]
+	if !ok {
+		return database.Feed{}, false
+	}
+
+	update(&feed)
+	m.data.feeds[id] = feed
+
+	return feed, true
+}
+
+
+// follows reports whether userID follows feedID, callers hold m.mu
+func (m *Memory) follows(userID uuid.UUID, feedID uuid.UUID) bool {
+	for _, follow := range m.data.follows {
+		if follow.UserID == userID && follow.FeedID == feedID {
+			return true
+		}
+	}
+
+	return false
+}
+
+
+// unreadCount counts the posts of feedID userID hasn't read, callers hold
+// m.mu
+func (m *Memory) unreadCount(userID uuid.UUID, feedID uuid.UUID) int64 {
+	var unread int64
+	for _, post := range m.data.posts {
+		if _, isRead := m.data.reads[readKey{userID, post.ID}]; post.FeedID == feedID && !isRead {
+			unread++
+		}
+	}
+
+	return unread
+}
+
+
+// newestPosts returns every post by publish date, newest first, callers
+// hold m.mu
+func (m *Memory) newestPosts() []database.Post {
+	posts := sortedBy(m.data.posts, func(p database.Post) time.Time { return p.PublishedAt })
+	slices.Reverse(posts)
+
+	return posts
+}
+
+
+// postBefore reports whether post sorts after the cursor in a newest first
+// listing, ties on the publish date go by id like the query
+func postBefore(post database.Post, publishedAt time.Time, id uuid.UUID) bool {
+	return cmp.Or(post.PublishedAt.Compare(publishedAt), cmp.Compare(post.ID.String(), id.String())) < 0
+}
+
+
+// sortedBy gives map rows a stable order, oldest first, with the id as a
+// tie breaker
+func sortedBy[T any](rows map[uuid.UUID]T, key func(T) time.Time) []T {
+	ids := slices.Collect(maps.Keys(rows))
+	slices.SortFunc(ids, func(a, b uuid.UUID) int {
+		return cmp.Or(key(rows[a]).Compare(key(rows[b])), cmp.Compare(a.String(), b.String()))
+	})
+
+	sorted := make([]T, 0, len(ids))
+	for _, id := range ids {
+		sorted = append(sorted, rows[id])
+	}
+
+	return sorted
+}
+
+
+// compareNullTime orders NULL before every time when nullsFirst is set,
+// after every time otherwise
+func compareNullTime(a sql.NullTime, b sql.NullTime, nullsFirst bool) int {
+	nullOrder := 1
+	if nullsFirst {
+		nullOrder = -1
+	}
+
+	switch {
+	case !a.Valid && !b.Valid:
+		return 0
+	case !a.Valid:
+		return nullOrder
+	case !b.Valid:
+		return -nullOrder
+	default:
+		return a.Time.Compare(b.Time)
+	}
+}
+
+
+func validTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+
+func seconds(n int32) time.Duration {
+	return time.Duration(n) * time.Second
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/internal/search"
+	"github.com/google/uuid"
+)
+
+// ErrNoFullTextSearch is returned by SearchPosts on stores without a
+// full-text index, their Search method scans posts instead
+var ErrNoFullTextSearch = errors.New("full-text search needs postgres")
+
+// scanPageSize is how many posts scanSearch reads at a time
+const scanPageSize = 500
+
+
+// scanSearch is Search for stores without a full-text index: it reads
+// every post of the followed feeds and ranks them by plain word matching
+func scanSearch(ctx context.Context, q database.Querier, arg SearchParams) ([]database.SearchPostsRow, error) {
+	feeds, err := q.GetFollowedFeeds(ctx, arg.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("get followed feeds: %w", err)
+	}
+
+	feedNames := make(map[uuid.UUID]string, len(feeds))
+	for _, feed := range feeds {
+		feedNames[feed.ID] = feed.Name
+	}
+
+	type match struct {
+		post database.GetPostsRow
+		rank float32
+	}
+
+	var matches []match
+	params := database.GetPostsParams{
+		UserID:   arg.UserID,
+		FeedID:   arg.FeedID,
+		PageSize: scanPageSize,
+	}
+
+	for {
+		posts, err := q.GetPosts(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("get posts: %w", err)
+		}
+
+		for _, post := range posts {
+			if rank := arg.Query.Rank(post.Title, post.Description.String); rank > 0 {
+				matches = append(matches, match{post: post, rank: rank})
+			}
+		}
+
+		if len(posts) < scanPageSize {
+			break
+		}
+
+		last := posts[len(posts)-1]
+		params.BeforePublishedAt = validTime(last.PublishedAt)
+		params.BeforeID = uuid.NullUUID{UUID: last.ID, Valid: true}
+	}
+
+	// posts come newest first, which breaks ties in rank like the query
+	slices.SortStableFunc(matches, func(a, b match) int { return cmp.Compare(b.rank, a.rank) })
+	matches = matches[:min(len(matches), int(arg.Limit))]
+
+	results := make([]database.SearchPostsRow, len(matches))
+	for i, m := range matches {
+		results[i] = database.SearchPostsRow{
+			ID:             m.post.ID,
+			Title:          m.post.Title,
+			Url:            m.post.Url,
+			PublishedAt:    m.post.PublishedAt,
+			FeedName:       feedNames[m.post.FeedID],
+			Rank:           m.rank,
+			TitleHighlight: arg.Query.Highlight(m.post.Title, false),
+			Snippet:        arg.Query.Highlight(search.StripHTML(m.post.Description.String), true),
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+)
+
+// sqlStore adds transactions to the sqlc queries of either engine, both
+// speak the same SAVEPOINT syntax
+type sqlStore struct {
+	database.Querier
+
+	engine Engine
+	db     *sql.DB
+	tx     *sql.Tx
+	withTx func(*sql.Tx) database.Querier
+}
+
+
+func (s *sqlStore) InTx(ctx context.Context, fn func(Store) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	txStore := &sqlStore{
+		Querier: s.withTx(tx),
+		engine:  s.engine,
+		db:      s.db,
+		tx:      tx,
+		withTx:  s.withTx,
+	}
+
+	if err := fn(txStore); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+
+func (s *sqlStore) Savepoint(ctx context.Context, fn func() error) error {
+	if s.tx == nil {
+		return fn()
+	}
+
+	if _, err := s.tx.ExecContext(ctx, "SAVEPOINT store_savepoint"); err != nil {
+		return fmt.Errorf("savepoint: %w", err)
+	}
+
+	if err := fn(); err != nil {
+		if _, rollbackErr := s.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT store_savepoint"); rollbackErr != nil {
+			return fmt.Errorf("rollback to savepoint: %w", rollbackErr)
+		}
+		if _, releaseErr := s.tx.ExecContext(ctx, "RELEASE SAVEPOINT store_savepoint"); releaseErr != nil {
+			return fmt.Errorf("release savepoint: %w", releaseErr)
+		}
+		return err
+	}
+
+	if _, err := s.tx.ExecContext(ctx, "RELEASE SAVEPOINT store_savepoint"); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+
+// Search uses the full-text index in Postgres, SQLite has none so its
+// posts are scanned
+func (s *sqlStore) Search(ctx context.Context, arg SearchParams) ([]database.SearchPostsRow, error) {
+	if s.engine == SQLite {
+		return scanSearch(ctx, s, arg)
+	}
+
+	params := database.SearchPostsParams{
+		Query:    arg.Query.TSQuery(),
+		UserID:   arg.UserID,
+		FeedID:   arg.FeedID,
+		PageSize: arg.Limit,
+	}
+
+	results, err := s.SearchPosts(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("search posts: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,235 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/internal/sqlite"
+	"github.com/google/uuid"
+)
+
+// sqliteQueries serves database.Querier from the SQLite queries. sqlc
+// generates both packages with the same field types, so rows and params
+// convert directly.
+type sqliteQueries struct {
+	q *sqlite.Queries
+}
+
+var _ database.Querier = (*sqliteQueries)(nil)
+
+
+func (s *sqliteQueries) ClaimFeed(ctx context.Context, arg database.ClaimFeedParams) (database.Feed, error) {
+	row, err := s.q.ClaimFeed(ctx, sqlite.ClaimFeedParams(arg))
+	return database.Feed(row), err
+}
+
+
+func (s *sqliteQueries) ClaimFeedsToFetch(ctx context.Context, arg database.ClaimFeedsToFetchParams) ([]database.Feed, error) {
+	rows, err := s.q.ClaimFeedsToFetch(ctx, sqlite.ClaimFeedsToFetchParams(arg))
+	return convertAll(rows, func(row sqlite.Feed) database.Feed { return database.Feed(row) }), err
+}
+
+
+func (s *sqliteQueries) ClearTableUsers(ctx context.Context) error {
+	return s.q.ClearTableUsers(ctx)
+}
+
+
+func (s *sqliteQueries) CountDueFeeds(ctx context.Context, overdueSeconds int32) (database.CountDueFeedsRow, error) {
+	row, err := s.q.CountDueFeeds(ctx, overdueSeconds)
+	return database.CountDueFeedsRow(row), err
+}
+
+
+func (s *sqliteQueries) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	row, err := s.q.CreateFeed(ctx, sqlite.CreateFeedParams(arg))
+	return database.Feed(row), err
+}
+
+
+func (s *sqliteQueries) CreateFeedFetch(ctx context.Context, arg database.CreateFeedFetchParams) error {
+	return s.q.CreateFeedFetch(ctx, sqlite.CreateFeedFetchParams(arg))
+}
+
+
+func (s *sqliteQueries) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	row, err := s.q.CreateFeedFollow(ctx, sqlite.CreateFeedFollowParams(arg))
+	return database.CreateFeedFollowRow(row), err
+}
+
+
+func (s *sqliteQueries) CreatePost(ctx context.Context, arg database.CreatePostParams) (database.CreatePostRow, error) {
+	row, err := s.q.CreatePost(ctx, sqlite.CreatePostParams(arg))
+	return database.CreatePostRow(row), err
+}
+
+
+func (s *sqliteQueries) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	row, err := s.q.CreateUser(ctx, sqlite.CreateUserParams(arg))
+	return database.User(row), err
+}
+
+
+func (s *sqliteQueries) DisableFeed(ctx context.Context, id uuid.UUID) error {
+	return s.q.DisableFeed(ctx, id)
+}
+
+
+func (s *sqliteQueries) EnableFeed(ctx context.Context, url sql.NullString) (int64, error) {
+	return s.q.EnableFeed(ctx, url)
+}
+
+
+func (s *sqliteQueries) GetFeed(ctx context.Context, url sql.NullString) (database.Feed, error) {
+	row, err := s.q.GetFeed(ctx, url)
+	return database.Feed(row), err
+}
+
+
+func (s *sqliteQueries) GetFeedFetches(ctx context.Context, arg database.GetFeedFetchesParams) ([]database.FeedFetch, error) {
+	rows, err := s.q.GetFeedFetches(ctx, sqlite.GetFeedFetchesParams(arg))
+	return convertAll(rows, func(row sqlite.FeedFetch) database.FeedFetch { return database.FeedFetch(row) }), err
+}
+
+
+func (s *sqliteQueries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsForUserRow, error) {
+	rows, err := s.q.GetFeedFollowsForUser(ctx, userID)
+	return convertAll(rows, func(row sqlite.GetFeedFollowsForUserRow) database.GetFeedFollowsForUserRow { return database.GetFeedFollowsForUserRow(row) }), err
+}
+
+
+func (s *sqliteQueries) GetFeedsByName(ctx context.Context, name string) ([]database.Feed, error) {
+	rows, err := s.q.GetFeedsByName(ctx, name)
+	return convertAll(rows, func(row sqlite.Feed) database.Feed { return database.Feed(row) }), err
+}
+
+
+func (s *sqliteQueries) GetFollowedFeeds(ctx context.Context, userID uuid.UUID) ([]database.Feed, error) {
+	rows, err := s.q.GetFollowedFeeds(ctx, userID)
+	return convertAll(rows, func(row sqlite.Feed) database.Feed { return database.Feed(row) }), err
+}
+
+
+func (s *sqliteQueries) GetPost(ctx context.Context, id uuid.UUID) (database.GetPostRow, error) {
+	row, err := s.q.GetPost(ctx, id)
+	return database.GetPostRow(row), err
+}
+
+
+func (s *sqliteQueries) GetPosts(ctx context.Context, arg database.GetPostsParams) ([]database.GetPostsRow, error) {
+	rows, err := s.q.GetPosts(ctx, sqlite.GetPostsParams(arg))
+	return convertAll(rows, func(row sqlite.GetPostsRow) database.GetPostsRow { return database.GetPostsRow(row) }), err
+}
+
+
+func (s *sqliteQueries) GetRecentPublishDates(ctx context.Context, arg database.GetRecentPublishDatesParams) ([]time.Time, error) {
+	return s.q.GetRecentPublishDates(ctx, sqlite.GetRecentPublishDatesParams(arg))
+}
+
+
+func (s *sqliteQueries) GetStarredPosts(ctx context.Context, userID uuid.UUID) ([]database.GetStarredPostsRow, error) {
+	rows, err := s.q.GetStarredPosts(ctx, userID)
+	return convertAll(rows, func(row sqlite.GetStarredPostsRow) database.GetStarredPostsRow { return database.GetStarredPostsRow(row) }), err
+}
+
+
+func (s *sqliteQueries) GetUser(ctx context.Context, name sql.NullString) (database.User, error) {
+	row, err := s.q.GetUser(ctx, name)
+	return database.User(row), err
+}
+
+
+func (s *sqliteQueries) GetUsers(ctx context.Context) ([]sql.NullString, error) {
+	return s.q.GetUsers(ctx)
+}
+
+
+func (s *sqliteQueries) ListBrokenFeeds(ctx context.Context) ([]database.Feed, error) {
+	rows, err := s.q.ListBrokenFeeds(ctx)
+	return convertAll(rows, func(row sqlite.Feed) database.Feed { return database.Feed(row) }), err
+}
+
+
+func (s *sqliteQueries) ListFeeds(ctx context.Context) ([]database.ListFeedsRow, error) {
+	rows, err := s.q.ListFeeds(ctx)
+	return convertAll(rows, func(row sqlite.ListFeedsRow) database.ListFeedsRow { return database.ListFeedsRow(row) }), err
+}
+
+
+func (s *sqliteQueries) MarkFetched(ctx context.Context, arg database.MarkFetchedParams) error {
+	return s.q.MarkFetched(ctx, sqlite.MarkFetchedParams(arg))
+}
+
+
+func (s *sqliteQueries) MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) error {
+	return s.q.MarkPostRead(ctx, sqlite.MarkPostReadParams(arg))
+}
+
+
+func (s *sqliteQueries) MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) (int64, error) {
+	return s.q.MarkPostUnread(ctx, sqlite.MarkPostUnreadParams(arg))
+}
+
+
+func (s *sqliteQueries) MarkPostsRead(ctx context.Context, arg database.MarkPostsReadParams) (int64, error) {
+	return s.q.MarkPostsRead(ctx, sqlite.MarkPostsReadParams(arg))
+}
+
+
+func (s *sqliteQueries) PruneFeedFetches(ctx context.Context, startedAt time.Time) (int64, error) {
+	return s.q.PruneFeedFetches(ctx, startedAt)
+}
+
+
+func (s *sqliteQueries) RecordFeedFailure(ctx context.Context, arg database.RecordFeedFailureParams) (database.Feed, error) {
+	row, err := s.q.RecordFeedFailure(ctx, sqlite.RecordFeedFailureParams(arg))
+	return database.Feed(row), err
+}
+
+
+func (s *sqliteQueries) ReleaseFeedClaim(ctx context.Context, arg database.ReleaseFeedClaimParams) error {
+	return s.q.ReleaseFeedClaim(ctx, sqlite.ReleaseFeedClaimParams(arg))
+}
+
+
+func (s *sqliteQueries) ScheduleFeed(ctx context.Context, arg database.ScheduleFeedParams) error {
+	return s.q.ScheduleFeed(ctx, sqlite.ScheduleFeedParams(arg))
+}
+
+
+// SearchPosts needs the Postgres full-text index, sqlStore.Search scans
+// SQLite instead
+func (s *sqliteQueries) SearchPosts(ctx context.Context, arg database.SearchPostsParams) ([]database.SearchPostsRow, error) {
+	return nil, ErrNoFullTextSearch
+}
+
+
+func (s *sqliteQueries) StarPost(ctx context.Context, arg database.StarPostParams) error {
+	return s.q.StarPost(ctx, sqlite.StarPostParams(arg))
+}
+
+
+func (s *sqliteQueries) UnfollowFeed(ctx context.Context, arg database.UnfollowFeedParams) error {
+	return s.q.UnfollowFeed(ctx, sqlite.UnfollowFeedParams(arg))
+}
+
+
+func (s *sqliteQueries) UnstarPost(ctx context.Context, arg database.UnstarPostParams) (int64, error) {
+	return s.q.UnstarPost(ctx, sqlite.UnstarPostParams(arg))
+}
+
+
+func convertAll[From, To any](rows []From, convert func(From) To) []To {
+	if rows == nil {
+		return nil
+	}
+
+	converted := make([]To, len(rows))
+	for i, row := range rows {
+		converted[i] = convert(row)
+	}
+
+	return converted
+}
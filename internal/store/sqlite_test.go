@@ -0,0 +1,358 @@
+package store_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/internal/migrate"
+	"github.com/OriElbaz/gatorcli/internal/search"
+	"github.com/OriElbaz/gatorcli/internal/store"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+
+func openSQLite(t *testing.T) store.Store {
+	t.Helper()
+
+	engine, dsn, err := store.ParseURL("sqlite://" + filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+
+	db, err := sql.Open(engine.Driver(), dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := migrate.Up(context.Background(), db, engine); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	return store.New(engine, db)
+}
+
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		engine store.Engine
+		isErr  bool
+	}{
+		{name: "postgres", url: "postgres://me:@localhost:5432/gator", engine: store.Postgres},
+		{name: "postgresql scheme", url: "postgresql://localhost/gator", engine: store.Postgres},
+		{name: "sqlite absolute path", url: "sqlite:///var/lib/gator.db", engine: store.SQLite},
+		{name: "sqlite without path", url: "sqlite://", isErr: true},
+		{name: "unknown scheme", url: "mysql://localhost/gator", isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			engine, _, err := store.ParseURL(tc.url)
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tc.url)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if engine != tc.engine {
+				t.Errorf("expected engine %q, got %q", tc.engine, engine)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestSQLiteStore(t *testing.T) {
+	ctx := context.Background()
+	s := openSQLite(t)
+	now := time.Now()
+
+	user, err := s.CreateUser(ctx, database.CreateUserParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      sql.NullString{String: "alice", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	feed, err := s.CreateFeed(ctx, database.CreateFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      "blog",
+		Url:       sql.NullString{String: "https://example.com/feed.xml", Valid: true},
+		UserID:    user.ID,
+	})
+	if err != nil {
+		t.Fatalf("create feed: %v", err)
+	}
+
+	follow, err := s.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		t.Fatalf("create feed follow: %v", err)
+	}
+	if follow.FeedName != "blog" || follow.UserName.String != "alice" {
+		t.Errorf("expected follow of blog by alice, got %q by %q", follow.FeedName, follow.UserName.String)
+	}
+
+	// posts published in different zones must come back newest first
+	published := []time.Time{
+		time.Date(2026, 10, 12, 10, 0, 0, 0, time.FixedZone("PDT", -7*3600)),
+		time.Date(2026, 10, 13, 10, 0, 0, 0, time.FixedZone("CEST", 2*3600)),
+		time.Date(2026, 10, 13, 9, 0, 0, 0, time.UTC),
+	}
+
+	err = s.InTx(ctx, func(tx store.Store) error {
+		for i, publishedAt := range published {
+			_, err := tx.CreatePost(ctx, database.CreatePostParams{
+				ID:          uuid.New(),
+				CreatedAt:   now,
+				UpdatedAt:   now,
+				Title:       fmt.Sprintf("post %d", i),
+				Url:         fmt.Sprintf("https://example.com/%d", i),
+				PublishedAt: publishedAt,
+				FeedID:      feed.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// a duplicate url inserts nothing and doesn't abort the transaction
+		return tx.Savepoint(ctx, func() error {
+			_, err := tx.CreatePost(ctx, database.CreatePostParams{
+				ID:          uuid.New(),
+				CreatedAt:   now,
+				UpdatedAt:   now,
+				Title:       "duplicate",
+				Url:         "https://example.com/0",
+				PublishedAt: now,
+				FeedID:      feed.ID,
+			})
+			if !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("expected sql.ErrNoRows for a duplicate, got %v", err)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("save posts: %v", err)
+	}
+
+	posts, err := s.GetPosts(ctx, database.GetPostsParams{UserID: user.ID, PageSize: 10})
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+
+	wantOrder := []string{"post 2", "post 1", "post 0"}
+	if len(posts) != len(wantOrder) {
+		t.Fatalf("expected %d posts, got %d", len(wantOrder), len(posts))
+	}
+	for i, title := range wantOrder {
+		if posts[i].Title != title {
+			t.Errorf("post %d: expected %q, got %q", i, title, posts[i].Title)
+		}
+	}
+
+	// filters and the cursor compare instants, not the stored strings
+	filters := []struct {
+		name   string
+		params database.GetPostsParams
+		want   []string
+	}{
+		{
+			name:   "since",
+			params: database.GetPostsParams{Since: sql.NullTime{Time: time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC), Valid: true}},
+			want:   []string{"post 2", "post 1"},
+		},
+		{
+			name:   "until",
+			params: database.GetPostsParams{Until: sql.NullTime{Time: time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC), Valid: true}},
+			want:   []string{"post 0"},
+		},
+		{
+			name: "cursor",
+			params: database.GetPostsParams{
+				BeforePublishedAt: sql.NullTime{Time: posts[0].PublishedAt, Valid: true},
+				BeforeID:          uuid.NullUUID{UUID: posts[0].ID, Valid: true},
+			},
+			want: []string{"post 1", "post 0"},
+		},
+		{
+			name:   "other feed",
+			params: database.GetPostsParams{FeedID: uuid.NullUUID{UUID: uuid.New(), Valid: true}},
+		},
+		{
+			name:   "offset",
+			params: database.GetPostsParams{PageSize: 1, PageOffset: 1},
+			want:   []string{"post 1"},
+		},
+	}
+
+	for _, filter := range filters {
+		filter.params.UserID = user.ID
+		if filter.params.PageSize == 0 {
+			filter.params.PageSize = 10
+		}
+
+		posts, err := s.GetPosts(ctx, filter.params)
+		if err != nil {
+			t.Fatalf("get posts by %s: %v", filter.name, err)
+		}
+
+		var titles []string
+		for _, post := range posts {
+			titles = append(titles, post.Title)
+		}
+		if fmt.Sprint(titles) != fmt.Sprint(filter.want) {
+			t.Errorf("get posts by %s: expected %v, got %v", filter.name, filter.want, titles)
+		}
+	}
+
+	err = s.MarkPostRead(ctx, database.MarkPostReadParams{UserID: user.ID, PostID: posts[0].ID, ReadAt: now})
+	if err != nil {
+		t.Fatalf("mark post read: %v", err)
+	}
+
+	unread, err := s.GetPosts(ctx, database.GetPostsParams{UserID: user.ID, UnreadOnly: true, PageSize: 10})
+	if err != nil {
+		t.Fatalf("get unread posts: %v", err)
+	}
+	if len(unread) != 2 || unread[0].Title != "post 1" || unread[0].ReadAt.Valid {
+		t.Errorf("expected post 1 and post 0 unread, got %d posts", len(unread))
+	}
+
+	follows, err := s.GetFeedFollowsForUser(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("get feed follows: %v", err)
+	}
+	if len(follows) != 1 || follows[0].UnreadCount != 2 {
+		t.Errorf("expected 2 unread posts in the followed feed, got %+v", follows)
+	}
+
+	marked, err := s.MarkPostsRead(ctx, database.MarkPostsReadParams{
+		ReadAt: now,
+		UserID: user.ID,
+		FeedID: uuid.NullUUID{UUID: feed.ID, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("mark posts read: %v", err)
+	}
+	if marked != 2 {
+		t.Errorf("expected 2 posts newly marked read, got %d", marked)
+	}
+
+	unmarked, err := s.MarkPostUnread(ctx, database.MarkPostUnreadParams{UserID: user.ID, PostID: posts[0].ID})
+	if err != nil {
+		t.Fatalf("mark post unread: %v", err)
+	}
+	if unmarked != 1 {
+		t.Errorf("expected 1 post marked unread, got %d", unmarked)
+	}
+
+	for i, post := range posts[:2] {
+		err := s.StarPost(ctx, database.StarPostParams{UserID: user.ID, PostID: post.ID, StarredAt: now.Add(time.Duration(i) * time.Minute)})
+		if err != nil {
+			t.Fatalf("star post: %v", err)
+		}
+	}
+
+	starred, err := s.GetStarredPosts(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("get starred posts: %v", err)
+	}
+	if len(starred) != 2 || starred[0].Title != "post 1" || starred[0].FeedName != "blog" {
+		t.Errorf("expected post 1 of blog starred last, got %+v", starred)
+	}
+
+	unstarred, err := s.UnstarPost(ctx, database.UnstarPostParams{UserID: user.ID, PostID: posts[1].ID})
+	if err != nil {
+		t.Fatalf("unstar post: %v", err)
+	}
+	if unstarred != 1 {
+		t.Errorf("expected 1 post unstarred, got %d", unstarred)
+	}
+
+	// sqlite has no tsvector, search falls back to scanning the posts
+	query, err := search.Parse("post -2")
+	if err != nil {
+		t.Fatalf("parse query: %v", err)
+	}
+
+	found, err := s.Search(ctx, store.SearchParams{UserID: user.ID, Query: query, Limit: 1})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(found) != 1 || found[0].Title != "post 1" || found[0].TitleHighlight != "**post** 1" || found[0].FeedName != "blog" {
+		t.Errorf("expected post 1 of blog as the best match, got %+v", found)
+	}
+
+	claimed, err := s.ClaimFeedsToFetch(ctx, database.ClaimFeedsToFetchParams{LeaseSeconds: 60, BatchSize: 10})
+	if err != nil {
+		t.Fatalf("claim feeds: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != feed.ID {
+		t.Fatalf("expected to claim the feed, got %d feeds", len(claimed))
+	}
+	lease := claimed[0].ClaimedUntil
+
+	claimed, err = s.ClaimFeedsToFetch(ctx, database.ClaimFeedsToFetchParams{LeaseSeconds: 60, BatchSize: 10})
+	if err != nil {
+		t.Fatalf("claim feeds again: %v", err)
+	}
+	if len(claimed) != 0 {
+		t.Errorf("expected a claimed feed to be skipped, got %d feeds", len(claimed))
+	}
+
+	// a stale lease must not release the one the feed holds now
+	stale := sql.NullTime{Time: lease.Time.Add(-time.Minute), Valid: true}
+	if err := s.ReleaseFeedClaim(ctx, database.ReleaseFeedClaimParams{ID: feed.ID, ClaimedUntil: stale}); err != nil {
+		t.Fatalf("release stale claim: %v", err)
+	}
+	if _, err := s.ClaimFeed(ctx, database.ClaimFeedParams{LeaseSeconds: 60, ID: feed.ID}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected the feed to stay claimed after a stale release, got %v", err)
+	}
+
+	if err := s.ReleaseFeedClaim(ctx, database.ReleaseFeedClaimParams{ID: feed.ID, ClaimedUntil: lease}); err != nil {
+		t.Fatalf("release claim: %v", err)
+	}
+	if _, err := s.ClaimFeed(ctx, database.ClaimFeedParams{LeaseSeconds: 60, ID: feed.ID}); err != nil {
+		t.Errorf("expected the feed to be claimable after its release, got %v", err)
+	}
+
+	updated, err := s.RecordFeedFailure(ctx, database.RecordFeedFailureParams{
+		LastError:      sql.NullString{String: "boom", Valid: true},
+		MaxFailures:    1,
+		RetryInSeconds: 60,
+		ID:             feed.ID,
+	})
+	if err != nil {
+		t.Fatalf("record feed failure: %v", err)
+	}
+	if !updated.DisabledAt.Valid || updated.ConsecutiveFailures != 1 {
+		t.Errorf("expected feed disabled after 1 failure, got disabled=%v failures=%d", updated.DisabledAt.Valid, updated.ConsecutiveFailures)
+	}
+
+	fmt.Printf("✅ Test Passed: sqlite store\n")
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/internal/search"
+	"github.com/OriElbaz/gatorcli/internal/sqlite"
+	"github.com/google/uuid"
+)
+
+// Store is what the commands read and write through, whichever database
+// is behind it
+type Store interface {
+	database.Querier
+
+	// InTx runs fn against a Store bound to a single transaction, which is
+	// committed if fn returns nil and rolled back otherwise
+	InTx(ctx context.Context, fn func(Store) error) error
+
+	// Savepoint runs fn so that inside InTx a failing fn only rolls back
+	// its own writes, not the whole transaction
+	Savepoint(ctx context.Context, fn func() error) error
+
+	// Search returns the posts matching a query among the feeds a user
+	// follows, best match first
+	Search(ctx context.Context, arg SearchParams) ([]database.SearchPostsRow, error)
+}
+
+
+// SearchParams scopes a search to the feeds UserID follows, or to one of
+// them when FeedID is set
+type SearchParams struct {
+	UserID uuid.UUID
+	FeedID uuid.NullUUID
+	Query  search.Query
+	Limit  int32
+}
+
+
+// Engine is the kind of database a db_url points at
+type Engine string
+
+const (
+	Postgres Engine = "postgres"
+	SQLite   Engine = "sqlite"
+)
+
+
+// Driver is the database/sql driver name for the engine
+func (e Engine) Driver() string {
+	if e == SQLite {
+		return "sqlite3"
+	}
+
+	return "postgres"
+}
+
+
+// ParseURL picks the engine from the url scheme and returns the data
+// source name to open it with. sqlite:///path/gator.db opens the file at
+// /path/gator.db, sqlite://gator.db one relative to the working directory.
+func ParseURL(dbURL string) (Engine, string, error) {
+	switch {
+	case strings.HasPrefix(dbURL, "postgres://"), strings.HasPrefix(dbURL, "postgresql://"):
+		return Postgres, dbURL, nil
+	case strings.HasPrefix(dbURL, "sqlite://"):
+		path := strings.TrimPrefix(dbURL, "sqlite://")
+		if path == "" {
+			return "", "", fmt.Errorf("sqlite url has no file path: %s", dbURL)
+		}
+
+		// foreign keys are off by default in SQLite, and immediate
+		// transactions make concurrent writers wait instead of failing
+		return SQLite, "file:" + path + "?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate", nil
+	default:
+		return "", "", fmt.Errorf("unsupported database url %q, use postgres:// or sqlite://", dbURL)
+	}
+}
+
+
+// New wraps an open database in the Store for its engine
+func New(engine Engine, db *sql.DB) Store {
+	if engine == SQLite {
+		return &sqlStore{
+			Querier: &sqliteQueries{q: sqlite.New(db)},
+			engine:  engine,
+			db:      db,
+			withTx: func(tx *sql.Tx) database.Querier {
+				return &sqliteQueries{q: sqlite.New(tx)}
+			},
+		}
+	}
+
+	return &sqlStore{
+		Querier: database.New(db),
+		engine:  engine,
+		db:      db,
+		withTx: func(tx *sql.Tx) database.Querier {
+			return database.New(tx)
+		},
+	}
+}
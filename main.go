@@ -1,29 +1,47 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"time"
 
 	"github.com/OriElbaz/gatorcli/internal/config"
-	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/internal/migrate"
+	"github.com/OriElbaz/gatorcli/internal/store"
 	"github.com/OriElbaz/gatorcli/pkg/commands"
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 
+// dbURLEnv overrides db_url from the config file
+const dbURLEnv = "GATOR_DB_URL"
 
-
-const dbURL = "postgres://orielbaz:@localhost:5432/gator?sslmode=disable"
+// pingTimeout bounds the connection check made before running a command
+const pingTimeout = 5 * time.Second
 
 
 func main() {
-	db, err := sql.Open("postgres", dbURL)
+	globalFlags := flag.NewFlagSet("gator", flag.ExitOnError)
+	logLevel := globalFlags.String("log-level", "info", "minimum log level: debug, info, warn or error")
+	logFormat := globalFlags.String("log-format", "text", "log output format: text or json")
+	dbFlag := globalFlags.String("db", "", "database url, overrides "+dbURLEnv+" and db_url in the config file")
+
+	// global flags go before the command name, the flag package stops
+	// parsing there and leaves the command and its arguments
+	globalFlags.Parse(os.Args[1:])
+
+	logger, err := commands.NewLogger(os.Stderr, *logLevel, *logFormat)
 	if err != nil {
-		fmt.Printf("Error connecting to db: %v", err)
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
 	}
-
-	dbQueries := database.New(db)
+	slog.SetDefault(logger)
 
 	configStruct, err := config.Read()
 	if err != nil {
@@ -31,9 +49,30 @@ func main() {
 		os.Exit(1)
 	}
 
+	dbURL := resolveDBURL(*dbFlag, configStruct)
+	if dbURL == "" {
+		fmt.Printf("ERROR: no database url, set db_url in ~/.gatorconfig.json, %s or --db\n", dbURLEnv)
+		os.Exit(1)
+	}
+
+	engine, dsn, err := store.ParseURL(dbURL)
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
+	}
+
+	db, err := openDB(engine, dsn, dbURL)
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
 	configState := commands.State{
-		Db:  dbQueries,
-		Cfg: &configStruct,
+		Db:     store.New(engine, db),
+		Conn:   db,
+		Engine: engine,
+		Cfg:    &configStruct,
 	}
 
 	commandMap := map[string]func(*commands.State, commands.Command) error{
@@ -42,34 +81,109 @@ func main() {
 		"reset": commands.Reset,
 		"users": commands.Users,
 		"agg": commands.Agg,
+		"fetch": commands.MiddlewareLoggedIn(commands.Fetch),
 		"addfeed": commands.MiddlewareLoggedIn(commands.AddFeed),
 		"feeds": commands.Feeds,
+		"feed": commands.Feed,
 		"follow": commands.MiddlewareLoggedIn(commands.Follow),
 		"following": commands.MiddlewareLoggedIn(commands.Following),
 		"unfollow": commands.MiddlewareLoggedIn(commands.Unfollow),
 		"browse": commands.MiddlewareLoggedIn(commands.Browse),
+		"read": commands.MiddlewareLoggedIn(commands.Read),
+		"unread": commands.MiddlewareLoggedIn(commands.Unread),
+		"star": commands.MiddlewareLoggedIn(commands.Star),
+		"unstar": commands.MiddlewareLoggedIn(commands.Unstar),
+		"starred": commands.MiddlewareLoggedIn(commands.Starred),
+		"search": commands.MiddlewareLoggedIn(commands.Search),
+		"migrate": commands.Migrate,
 	}
 
 	commandsStruct := commands.Commands{
 		Commands: commandMap,
 	}
 
-	commandLineInputs := os.Args
-	if len(commandLineInputs) < 2 {
+	commandLineInputs := globalFlags.Args()
+	if len(commandLineInputs) < 1 {
 		fmt.Println("incorrect number of arguments")
 		os.Exit(1)
 	}
 
-	commandName := commandLineInputs[1]
-	commandArgs := commandLineInputs[2:]
+	commandName := commandLineInputs[0]
+	commandArgs := commandLineInputs[1:]
 	commandToRun := commands.Command{
 		Name:      commandName,
 		Arguments: commandArgs,
 	}
 
+	// every other command needs the schema this binary was built against
+	if commandName != "migrate" {
+		if err := checkSchema(db, engine); err != nil {
+			fmt.Printf("ERROR: %v\n", err)
+			db.Close()
+			os.Exit(1)
+		}
+	}
+
 	if err = commandsStruct.Run(&configState, commandToRun); err != nil {
 	fmt.Printf("ERROR: %v", err)
+	db.Close()
 	os.Exit(1)
 	}
 
 }
+
+
+// resolveDBURL picks the database url from, in order, --db, GATOR_DB_URL
+// and the config file
+func resolveDBURL(flagValue string, cfg config.Config) string {
+	if flagValue != "" {
+		return flagValue
+	}
+
+	if envValue := os.Getenv(dbURLEnv); envValue != "" {
+		return envValue
+	}
+
+	return cfg.DbURL
+}
+
+
+// openDB opens the database and pings it, so a bad url or a server that
+// is down fails here instead of in the middle of a command
+func openDB(engine store.Engine, dsn string, dbURL string) (*sql.DB, error) {
+	db, err := sql.Open(engine.Driver(), dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database %s: %w", redactURL(dbURL), err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect to database %s: %w", redactURL(dbURL), err)
+	}
+
+	return db, nil
+}
+
+
+// redactURL hides the password so the url can be printed in errors
+func redactURL(dbURL string) string {
+	parsed, err := url.Parse(dbURL)
+	if err != nil {
+		return "(invalid url)"
+	}
+
+	return parsed.Redacted()
+}
+
+
+// checkSchema compares the database's migration version with the latest
+// one embedded in the binary
+func checkSchema(db *sql.DB, engine store.Engine) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	return migrate.Check(ctx, db, engine)
+}
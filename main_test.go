@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/OriElbaz/gatorcli/internal/config"
+)
+
+
+func TestResolveDBURL(t *testing.T) {
+	const (
+		flagURL   = "postgres://flag@localhost/gator"
+		envURL    = "postgres://env@localhost/gator"
+		configURL = "postgres://config@localhost/gator"
+	)
+
+	tests := []struct {
+		name     string
+		flag     string
+		env      string
+		config   string
+		expected string
+	}{
+		{name: "Flag Wins", flag: flagURL, env: envURL, config: configURL, expected: flagURL},
+		{name: "Flag Without Env", flag: flagURL, config: configURL, expected: flagURL},
+		{name: "Env Over Config", env: envURL, config: configURL, expected: envURL},
+		{name: "Config Only", config: configURL, expected: configURL},
+		{name: "Env Only", env: envURL, expected: envURL},
+		{name: "Nothing Set", expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Setenv restores the variable once the test is done, even
+			// after it is unset below
+			t.Setenv(dbURLEnv, tc.env)
+			if tc.env == "" {
+				os.Unsetenv(dbURLEnv)
+			}
+
+			got := resolveDBURL(tc.flag, config.Config{DbURL: tc.config})
+			if got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
@@ -0,0 +1,759 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/internal/store"
+	"github.com/OriElbaz/gatorcli/pkg/rss"
+	"github.com/google/uuid"
+)
+
+
+// claimLease is how long a claimed feed is hidden from other aggregators,
+// so a crashed process only holds on to its feeds until the lease expires
+const claimLease = 10 * time.Minute
+
+// maxBackoff caps how long a failing feed waits before its next fetch
+const maxBackoff = 24 * time.Hour
+
+// defaultPollInterval is used by agg --once when no interval is given
+const defaultPollInterval = time.Hour
+
+// defaultMaxFailures is how many scrapes in a row may fail before agg
+// disables a feed
+const defaultMaxFailures = 10
+
+
+const (
+	outcomeInserted  = "inserted"
+	outcomeDuplicate = "duplicate"
+	outcomeFailed    = "failed"
+)
+
+
+/***** STRUCTS *****/
+type scrapeSummary struct {
+	Feed  string
+	Items []itemResult
+}
+
+
+type itemResult struct {
+	Title   string
+	Link    string
+	Outcome string
+	Err     error
+}
+
+
+func (s scrapeSummary) count(outcome string) int {
+	n := 0
+	for _, item := range s.Items {
+		if item.Outcome == outcome {
+			n++
+		}
+	}
+
+	return n
+}
+
+
+func (s scrapeSummary) String() string {
+	return fmt.Sprintf("%s: %d inserted, %d skipped (duplicate), %d failed",
+		s.Feed, s.count(outcomeInserted), s.count(outcomeDuplicate), s.count(outcomeFailed))
+}
+
+
+// scrapeOptions control how a scrape reschedules its feed
+type scrapeOptions struct {
+	interval    time.Duration
+	maxFailures int
+
+	// verbose also prints the items that were already saved
+	verbose bool
+}
+
+
+// aggregator hands due feeds to a fixed pool of workers. Only the
+// dispatcher claims feeds, and a feed stays in inFlight until its worker
+// is done, so no two workers ever fetch the same feed.
+type aggregator struct {
+	s           *State
+	opts        scrapeOptions
+	batch       int
+	perHost     int
+	keepHistory time.Duration
+
+	// once makes dispatch wait for a free worker instead of leaving feeds
+	// for the next tick, and skip feeds already fetched in this run
+	once bool
+
+	jobs    chan database.Feed
+	workers sync.WaitGroup
+
+	mu       sync.Mutex
+	inFlight map[uuid.UUID]bool
+	done     map[uuid.UUID]bool
+	failed   int
+	hosts    map[string]chan struct{}
+
+	// freed wakes a once run whose due feeds were all on saturated hosts
+	freed chan struct{}
+}
+
+
+/****** COMMANDS ******/
+func Agg(s *State, cmd Command) error {
+	flags := flag.NewFlagSet("agg", flag.ContinueOnError)
+	workers := flags.Int("workers", 4, "number of feeds fetched in parallel")
+	batch := flags.Int("batch", 0, "maximum feeds claimed per tick (default: workers)")
+	perHost := flags.Int("per-host", 2, "maximum concurrent fetches per host")
+	maxFailures := flags.Int("max-failures", defaultMaxFailures, "consecutive failures before a feed is disabled")
+	once := flags.Bool("once", false, "fetch every due feed once and exit")
+	keepHistory := flags.Duration("keep-history", defaultHistoryRetention, "how long to keep fetch history")
+	metricsAddr := flags.String("metrics-addr", "", "serve Prometheus metrics on this address, e.g. :9090")
+
+	args, err := parseArgs(flags, cmd.Arguments)
+	if err != nil {
+		return fmt.Errorf("parse arguments: %w", err)
+	}
+	if len(args) > 1 || (len(args) == 0 && !*once) {
+		return fmt.Errorf("usage: agg <time_between_reqs> [--workers N] [--batch M] [--per-host K] [--max-failures F] [--keep-history D] [--metrics-addr ADDR] [--once]")
+	}
+	if *workers < 1 || *perHost < 1 || *maxFailures < 1 {
+		return fmt.Errorf("--workers, --per-host and --max-failures must be at least 1")
+	}
+	if *batch < 1 {
+		*batch = *workers
+	}
+
+	timeBetweenRequests := defaultPollInterval
+	if len(args) == 1 {
+		timeBetweenRequests, err = time.ParseDuration(args[0])
+		if err != nil {
+			return fmt.Errorf("parse duration: %w", err)
+		}
+	}
+
+	agg := &aggregator{
+		s: s,
+		opts: scrapeOptions{
+			interval:    timeBetweenRequests,
+			maxFailures: *maxFailures,
+		},
+		batch:       *batch,
+		perHost:     *perHost,
+		keepHistory: *keepHistory,
+		once:        *once,
+		jobs:        make(chan database.Feed, *batch),
+		inFlight:    map[uuid.UUID]bool{},
+		done:        map[uuid.UUID]bool{},
+		hosts:       map[string]chan struct{}{},
+		freed:       make(chan struct{}, 1),
+	}
+
+	// Ctrl-C or SIGTERM cancels in-flight fetches and inserts, whose
+	// transactions roll back, and releases every claimed feed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *metricsAddr != "" {
+		if err := serveMetrics(ctx, *metricsAddr); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < *workers; i++ {
+		agg.workers.Add(1)
+		go agg.work(ctx)
+	}
+
+	if *once {
+		err = agg.runOnce(ctx)
+	} else {
+		slog.Info("aggregator started", "interval", timeBetweenRequests, "workers", *workers)
+		agg.run(ctx, timeBetweenRequests)
+	}
+
+	close(agg.jobs)
+	agg.workers.Wait()
+
+	if ctx.Err() != nil {
+		slog.Info("aggregator stopped")
+
+		// a run cut short must not look like a clean pass to cron
+		if *once && err == nil {
+			err = fmt.Errorf("interrupted before every due feed was fetched: %w", ctx.Err())
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// a long-running agg retries failed feeds on later ticks, only a
+	// single pass reports them through its exit status
+	if *once && agg.failed > 0 {
+		return fmt.Errorf("%d feeds failed", agg.failed)
+	}
+
+	return nil
+}
+
+
+// scrapeFeed fetches one feed, saves its new posts, schedules its next
+// fetch and logs the attempt to feed_fetches
+func scrapeFeed(ctx context.Context, s *State, feedToFetch database.Feed, opts scrapeOptions) (summary scrapeSummary, err error) {
+	start := time.Now()
+	feed := &rss.RSSFeed{}
+	log := feedLogger(feedToFetch)
+
+	defer func() {
+		observeFetch(start, feed, summary, err)
+		recordFetch(ctx, s, feedToFetch.ID, start, feed, summary, err)
+		logFetch(log, start, feed, summary, err)
+	}()
+
+	cache := rss.CacheValidators{
+		ETag:         feedToFetch.Etag.String,
+		LastModified: feedToFetch.LastModified.String,
+	}
+
+	feed, err = rss.FetchFeedIfModified(ctx, feedToFetch.Url.String, cache)
+	if err != nil {
+		return scrapeSummary{}, fmt.Errorf("fetch feed: %w", err)
+	}
+
+	interval := feedInterval(feedToFetch, opts)
+
+	if feed.NotModified {
+		return scrapeSummary{Feed: feedToFetch.Name}, markFetched(ctx, s, feedToFetch, feed, interval)
+	}
+
+	summary, err = savePosts(ctx, log, s, feedToFetch.ID, feed.Channel.Item)
+	if err != nil {
+		dbErrors.WithLabelValues("save_posts").Inc()
+		return scrapeSummary{}, fmt.Errorf("save posts: %w", err)
+	}
+	summary.Feed = feed.Channel.Title
+
+	for _, item := range summary.Items {
+		switch {
+		case item.Outcome == outcomeInserted:
+			log.Info("post created", "title", item.Title)
+		case item.Outcome == outcomeFailed:
+			log.Warn("post failed", "title", item.Title, "link", item.Link, "error", item.Err)
+		case opts.verbose:
+			log.Info("post skipped (duplicate)", "title", item.Title)
+		default:
+			log.Debug("post skipped (duplicate)", "title", item.Title)
+		}
+	}
+
+	// the posts are saved, so a failed lookup only keeps the old interval
+	learned, learnErr := learnInterval(ctx, s, feedToFetch, feed.Hints, opts)
+	if learnErr != nil {
+		dbErrors.WithLabelValues("get_recent_publish_dates").Inc()
+		log.Error("learn interval", "error", learnErr)
+	} else {
+		interval = learned
+	}
+
+	return summary, markFetched(ctx, s, feedToFetch, feed, interval)
+}
+
+
+/** AGGREGATOR **/
+
+// run dispatches due feeds on every tick until ctx is cancelled
+func (a *aggregator) run(ctx context.Context, interval time.Duration) {
+	// feeds learn their own interval, so look for due ones at least
+	// once a minute even when the default interval is longer
+	ticker := time.NewTicker(min(interval, time.Minute))
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+
+	for {
+		if time.Since(lastPrune) >= pruneEvery {
+			if err := pruneHistory(ctx, a.s, a.keepHistory); err != nil && ctx.Err() == nil {
+				slog.Error("prune fetch history", "error", err)
+			}
+			lastPrune = time.Now()
+		}
+
+		if _, _, err := a.dispatch(ctx); err != nil {
+			if ctx.Err() == nil {
+				slog.Error("dispatch feeds", "error", err)
+			}
+		} else {
+			lastCycle.SetToCurrentTime()
+		}
+
+		if err := updateDueFeeds(ctx, a.s); err != nil && ctx.Err() == nil {
+			slog.Error("update due feeds", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+
+// runOnce keeps claiming until no feed is due that this run hasn't
+// fetched yet
+func (a *aggregator) runOnce(ctx context.Context) error {
+	if err := pruneHistory(ctx, a.s, a.keepHistory); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		queued, deferred, err := a.dispatch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		lastCycle.SetToCurrentTime()
+
+		if queued > 0 {
+			continue
+		}
+		if deferred == 0 {
+			return nil
+		}
+
+		// every feed left is on a host at its limit, wait for a fetch
+		// to finish before claiming them again
+		select {
+		case <-a.freed:
+		case <-ctx.Done():
+		}
+	}
+
+	return nil
+}
+
+
+// dispatch claims up to a batch of due feeds and queues them for the
+// workers. The claim is a lease on the feeds row, so other agg processes
+// sharing the database skip these feeds until they are released. A feed
+// whose host already has perHost fetches queued or running is handed
+// back and counted as deferred, so it can't hold up feeds on other hosts.
+func (a *aggregator) dispatch(ctx context.Context) (queued int, deferred int, err error) {
+	params := database.ClaimFeedsToFetchParams{
+		LeaseSeconds: int32(claimLease.Seconds()),
+		BatchSize:    int32(a.batch),
+	}
+
+	feeds, err := a.s.Db.ClaimFeedsToFetch(ctx, params)
+	if err != nil {
+		dbErrors.WithLabelValues("claim_feeds_to_fetch").Inc()
+		return 0, 0, fmt.Errorf("claim feeds to fetch: %w", err)
+	}
+
+	for _, feed := range feeds {
+		claimed, inFlight := a.claim(feed.ID)
+		if inFlight {
+			// our own lease expired while a worker still has this feed
+			continue
+		}
+		if !claimed {
+			// already fetched in this run
+			a.release(ctx, feed)
+			continue
+		}
+
+		if !a.takeSlot(feed) {
+			a.requeue(ctx, feed)
+			deferred++
+			continue
+		}
+
+		if a.once {
+			select {
+			case a.jobs <- feed:
+				queued++
+			case <-ctx.Done():
+				a.freeSlot(feed)
+				a.release(ctx, feed)
+			}
+			continue
+		}
+
+		select {
+		case a.jobs <- feed:
+			queued++
+		default:
+			// every worker is busy and the queue is full, retry next tick
+			a.freeSlot(feed)
+			a.release(ctx, feed)
+		}
+	}
+
+	return queued, deferred, nil
+}
+
+
+func (a *aggregator) work(ctx context.Context) {
+	defer a.workers.Done()
+
+	for feed := range a.jobs {
+		if ctx.Err() != nil {
+			// shutting down, hand queued feeds back untouched
+			a.freeSlot(feed)
+			a.release(ctx, feed)
+			continue
+		}
+
+		a.fetch(ctx, feed)
+	}
+}
+
+
+func (a *aggregator) fetch(ctx context.Context, feed database.Feed) {
+	defer a.release(ctx, feed)
+	defer a.freeSlot(feed)
+
+	_, err := scrapeFeed(ctx, a.s, feed, a.opts)
+	if err == nil {
+		return
+	}
+
+	if ctx.Err() != nil {
+		// cancelled by shutdown, not the feed's fault
+		return
+	}
+
+	a.mu.Lock()
+	a.failed++
+	a.mu.Unlock()
+
+	handleScrapeError(ctx, a.s, feed, err, a.opts)
+}
+
+
+// claim marks a feed as being fetched by this process. It fails if a
+// worker already has the feed, or in once mode if the feed was already
+// fetched in this run.
+func (a *aggregator) claim(feedID uuid.UUID) (claimed bool, inFlight bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.inFlight[feedID] {
+		return false, true
+	}
+
+	if a.once && a.done[feedID] {
+		return false, false
+	}
+
+	a.inFlight[feedID] = true
+	if a.once {
+		a.done[feedID] = true
+	}
+	return true, false
+}
+
+
+// release hands a feed back to every aggregator. It runs even after ctx
+// is cancelled, so a stopped agg doesn't hold on to its leases. Only the
+// lease the feed was claimed with is released, a fetch that outlived it
+// leaves whoever claimed the feed next alone.
+func (a *aggregator) release(ctx context.Context, feed database.Feed) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := a.s.Db.ReleaseFeedClaim(ctx, releaseParams(feed)); err != nil {
+		dbErrors.WithLabelValues("release_feed_claim").Inc()
+		slog.Error("release feed claim", "feed_id", feed.ID, "error", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.inFlight, feed.ID)
+}
+
+
+// requeue hands back a feed that couldn't be queued yet, so a later
+// dispatch claims it again even in once mode
+func (a *aggregator) requeue(ctx context.Context, feed database.Feed) {
+	a.mu.Lock()
+	delete(a.done, feed.ID)
+	a.mu.Unlock()
+
+	a.release(ctx, feed)
+}
+
+
+// takeSlot reserves one of the feed host's slots, without waiting for
+// one when the host is at its limit
+func (a *aggregator) takeSlot(feed database.Feed) bool {
+	select {
+	case a.hostSlot(feedHost(feed.Url.String)) <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+
+// freeSlot gives back the host slot taken when the feed was queued
+func (a *aggregator) freeSlot(feed database.Feed) {
+	<-a.hostSlot(feedHost(feed.Url.String))
+
+	select {
+	case a.freed <- struct{}{}:
+	default:
+	}
+}
+
+
+// hostSlot returns the semaphore bounding concurrent fetches to one host
+func (a *aggregator) hostSlot(host string) chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	slot, ok := a.hosts[host]
+	if !ok {
+		slot = make(chan struct{}, a.perHost)
+		a.hosts[host] = slot
+	}
+
+	return slot
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// markFetched stores the new cache validators and polling interval and
+// schedules the next fetch
+func markFetched(ctx context.Context, s *State, feedToFetch database.Feed, feed *rss.RSSFeed, interval time.Duration) error {
+	delay := nextFetchDelay(time.Now(), interval, feed.Hints)
+
+	params := database.MarkFetchedParams{
+		Etag:                nullString(feed.Cache.ETag),
+		LastModified:        nullString(feed.Cache.LastModified),
+		PollIntervalSeconds: sql.NullInt32{Int32: int32(interval.Seconds()), Valid: true},
+		NextFetchInSeconds:  int32(delay.Seconds()),
+		ID:                  feedToFetch.ID,
+	}
+
+	if err := s.Db.MarkFetched(ctx, params); err != nil {
+		dbErrors.WithLabelValues("mark_fetched").Inc()
+		return fmt.Errorf("mark fetched: %w", err)
+	}
+
+	return nil
+}
+
+
+// handleScrapeError records the failure on the feed, disabling it once
+// it has failed maxFailures times in a row and backing off its next fetch
+// exponentially, and reacts to the typed errors returned by rss.FetchFeed
+func handleScrapeError(ctx context.Context, s *State, feed database.Feed, err error, opts scrapeOptions) {
+	var (
+		gone        *rss.GoneError
+		rateLimited *rss.RateLimitedError
+		serverErr   *rss.ServerError
+	)
+
+	log := feedLogger(feed)
+
+	if errors.As(err, &rateLimited) {
+		// being throttled says nothing about the feed itself, so only wait
+		retryIn := max(rateLimited.RetryAfter, feedInterval(feed, opts))
+
+		params := database.ScheduleFeedParams{
+			RetryInSeconds: int32(retryIn.Seconds()),
+			ID:             feed.ID,
+		}
+
+		if err := s.Db.ScheduleFeed(ctx, params); err != nil {
+			dbErrors.WithLabelValues("schedule_feed").Inc()
+			log.Error("schedule feed", "error", err)
+		}
+
+		log.Info("feed rate limited", "retry_in", retryIn)
+		return
+	}
+
+	retryIn := backoff(feedInterval(feed, opts), feed.ConsecutiveFailures+1)
+	if errors.As(err, &serverErr) {
+		retryIn = max(retryIn, serverErr.RetryAfter)
+	}
+
+	params := database.RecordFeedFailureParams{
+		LastError:      nullString(err.Error()),
+		MaxFailures:    int32(opts.maxFailures),
+		RetryInSeconds: int32(retryIn.Seconds()),
+		ID:             feed.ID,
+	}
+
+	updated, recordErr := s.Db.RecordFeedFailure(ctx, params)
+	if recordErr != nil {
+		dbErrors.WithLabelValues("record_feed_failure").Inc()
+		log.Error("record feed failure", "error", recordErr)
+	}
+
+	if errors.As(err, &gone) {
+		if err := s.Db.DisableFeed(ctx, feed.ID); err != nil {
+			dbErrors.WithLabelValues("disable_feed").Inc()
+			log.Error("disable feed", "error", err)
+			return
+		}
+		log.Warn("feed is gone, disabled")
+		return
+	}
+
+	if recordErr == nil && updated.DisabledAt.Valid {
+		log.Warn("feed disabled after too many failures",
+			"failures", updated.ConsecutiveFailures,
+			"enable_with", "gator feed enable "+feed.Url.String)
+		return
+	}
+
+	log.Info("feed will be retried", "retry_in", retryIn, "failures", feed.ConsecutiveFailures+1)
+}
+
+
+// backoff doubles the polling interval for every consecutive failure,
+// capped at maxBackoff
+func backoff(interval time.Duration, failures int32) time.Duration {
+	wait := interval
+	for i := int32(1); i < failures && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+
+	return min(wait, maxBackoff)
+}
+
+
+// logFetch writes the one record every scrape ends with
+func logFetch(log *slog.Logger, start time.Time, feed *rss.RSSFeed, summary scrapeSummary, err error) {
+	attrs := []any{
+		"outcome", fetchOutcome(feed, err),
+		"duration", time.Since(start).Round(time.Millisecond),
+	}
+
+	if err != nil {
+		log.Warn("feed fetch failed", append(attrs, "error", err)...)
+		return
+	}
+
+	log.Info("feed fetched", append(attrs,
+		"inserted", summary.count(outcomeInserted),
+		"duplicates", summary.count(outcomeDuplicate),
+		"failed", summary.count(outcomeFailed),
+	)...)
+}
+
+
+// releaseParams releases the lease a claim query returned the feed with
+func releaseParams(claimed database.Feed) database.ReleaseFeedClaimParams {
+	return database.ReleaseFeedClaimParams{
+		ID:           claimed.ID,
+		ClaimedUntil: claimed.ClaimedUntil,
+	}
+}
+
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+
+func feedHost(feedURL string) string {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return feedURL
+	}
+
+	return strings.ToLower(parsed.Hostname())
+}
+
+// savePosts inserts every item of a feed in one transaction. Each insert
+// runs under its own savepoint so a failing item is rolled back and
+// recorded without aborting the rest.
+func savePosts(ctx context.Context, log *slog.Logger, s *State, feedID uuid.UUID, items []rss.RSSItem) (scrapeSummary, error) {
+	summary := scrapeSummary{}
+
+	err := s.Db.InTx(ctx, func(tx store.Store) error {
+		for _, item := range items {
+			if item.DateFallback {
+				log.Warn("no valid publish date, using fetch time", "title", item.Title, "pub_date", item.PubDate)
+			}
+
+			result := itemResult{Title: item.Title, Link: item.Link}
+
+			if strings.TrimSpace(item.Link) == "" {
+				result.Outcome, result.Err = outcomeFailed, errors.New("missing link")
+				summary.Items = append(summary.Items, result)
+				continue
+			}
+
+			params := database.CreatePostParams{
+				ID: uuid.New(),
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+				Title: item.Title,
+				Url: item.Link,
+				Description: sql.NullString{
+					String: item.Description,
+					Valid: true,
+				},
+				PublishedAt: item.PublishedAt,
+				FeedID: feedID,
+			}
+
+			var createErr error
+			err := tx.Savepoint(ctx, func() error {
+				_, createErr = tx.CreatePost(ctx, params)
+				if errors.Is(createErr, sql.ErrNoRows) {
+					return nil
+				}
+				return createErr
+			})
+			if err != nil && err != createErr {
+				return err
+			}
+
+			switch {
+			case errors.Is(createErr, sql.ErrNoRows):
+				// ON CONFLICT DO NOTHING returns no row for a post we already have
+				result.Outcome = outcomeDuplicate
+			case createErr != nil:
+				result.Outcome, result.Err = outcomeFailed, createErr
+			default:
+				result.Outcome = outcomeInserted
+			}
+			summary.Items = append(summary.Items, result)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return scrapeSummary{}, err
+	}
+
+	return summary, nil
+}
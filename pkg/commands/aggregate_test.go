@@ -0,0 +1,333 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/internal/store"
+	"github.com/OriElbaz/gatorcli/pkg/rss"
+	"github.com/google/uuid"
+)
+
+
+func TestSavePosts(t *testing.T) {
+	s, alice := newTestState(t)
+	feed := addTestFeed(t, s, alice, "Example", "https://example.com/feed.xml")
+	if _, err := createFeedFollowHelper(s, alice.ID, feed.ID); err != nil {
+		t.Fatalf("follow feed: %v", err)
+	}
+	addTestPost(t, s, feed, "Saved Earlier", time.Now().Add(-time.Hour))
+
+	published := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	items := []rss.RSSItem{
+		{Title: "Good", Link: "https://example.com/good", PublishedAt: published},
+		{Title: "No Link", Link: " ", PublishedAt: published},
+		{Title: "Duplicate", Link: "https://example.com/feed.xml#Saved Earlier", PublishedAt: published},
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	summary, err := savePosts(context.Background(), log, s, feed.ID, items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedOutcomes := []string{outcomeInserted, outcomeFailed, outcomeDuplicate}
+	if len(summary.Items) != len(expectedOutcomes) {
+		t.Fatalf("expected %d item results, got %d", len(expectedOutcomes), len(summary.Items))
+	}
+	for i, item := range summary.Items {
+		if item.Outcome != expectedOutcomes[i] {
+			t.Errorf("%s: expected %s, got %s", item.Title, expectedOutcomes[i], item.Outcome)
+		}
+	}
+
+	titles := feedPostTitles(t, s, alice, feed)
+	expected := []string{"Saved Earlier", "Good"}
+	slices.Sort(titles)
+	slices.Sort(expected)
+	if !slices.Equal(titles, expected) {
+		t.Errorf("expected posts %v, got %v", expected, titles)
+	}
+	fmt.Printf("✅ Test Passed: save posts\n")
+}
+
+
+func TestDispatchSkipsSaturatedHosts(t *testing.T) {
+	s, alice := newTestState(t)
+	addTestFeed(t, s, alice, "Busy 1", "https://busy.example.com/1.xml")
+	addTestFeed(t, s, alice, "Busy 2", "https://busy.example.com/2.xml")
+	addTestFeed(t, s, alice, "Quiet", "https://quiet.example.com/feed.xml")
+
+	// no workers run, so queued feeds keep their host slot
+	agg := &aggregator{
+		s:        s,
+		batch:    3,
+		perHost:  1,
+		once:     true,
+		jobs:     make(chan database.Feed, 3),
+		inFlight: map[uuid.UUID]bool{},
+		done:     map[uuid.UUID]bool{},
+		hosts:    map[string]chan struct{}{},
+		freed:    make(chan struct{}, 1),
+	}
+
+	queued, deferred, err := agg.dispatch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queued != 2 || deferred != 1 {
+		t.Fatalf("expected 2 queued and 1 deferred, got %d and %d", queued, deferred)
+	}
+
+	var names []string
+	for range queued {
+		names = append(names, (<-agg.jobs).Name)
+	}
+	if !slices.Contains(names, "Quiet") {
+		t.Errorf("expected the feed on the free host to be queued, got %v", names)
+	}
+
+	// the deferred feed is claimable again once its host frees up
+	agg.freeSlot(database.Feed{Url: nullString("https://busy.example.com/1.xml")})
+	queued, deferred, err = agg.dispatch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queued != 1 || deferred != 0 {
+		t.Errorf("expected the deferred feed queued, got %d queued and %d deferred", queued, deferred)
+	}
+	fmt.Printf("✅ Test Passed: dispatch skips saturated hosts\n")
+}
+
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		failures int32
+		expected time.Duration
+	}{
+		{name: "First Failure", interval: time.Hour, failures: 1, expected: time.Hour},
+		{name: "Second Failure", interval: time.Hour, failures: 2, expected: 2 * time.Hour},
+		{name: "Fourth Failure", interval: time.Hour, failures: 4, expected: 8 * time.Hour},
+		{name: "Capped At A Day", interval: time.Hour, failures: 6, expected: maxBackoff},
+		{name: "Many Failures", interval: 30 * time.Minute, failures: 1000, expected: maxBackoff},
+		{name: "Interval Past The Cap", interval: 48 * time.Hour, failures: 1, expected: maxBackoff},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := backoff(tc.interval, tc.failures); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestHandleScrapeError(t *testing.T) {
+	tests := []struct {
+		name             string
+		failures         int32
+		maxFailures      int
+		err              error
+		expectedWait     time.Duration
+		expectedFailures int32
+		disabled         bool
+	}{
+		{name: "First Failure", err: errors.New("boom"), expectedWait: time.Hour, expectedFailures: 1},
+		{name: "Doubles Per Failure", failures: 2, err: errors.New("boom"), expectedWait: 4 * time.Hour, expectedFailures: 3},
+		{name: "Capped At A Day", failures: 8, err: errors.New("boom"), expectedWait: maxBackoff, expectedFailures: 9},
+		{
+			name:             "Longer Retry-After Wins",
+			err:              &rss.ServerError{StatusCode: 503, RetryAfter: 10 * time.Hour},
+			expectedWait:     10 * time.Hour,
+			expectedFailures: 1,
+		},
+		{
+			name:             "Shorter Retry-After Loses",
+			failures:         1,
+			err:              &rss.ServerError{StatusCode: 503, RetryAfter: time.Minute},
+			expectedWait:     2 * time.Hour,
+			expectedFailures: 2,
+		},
+		{
+			name:             "Rate Limited Isn't A Failure",
+			failures:         1,
+			err:              &rss.RateLimitedError{RetryAfter: 3 * time.Hour},
+			expectedWait:     3 * time.Hour,
+			expectedFailures: 1,
+		},
+		{name: "Gone", err: &rss.GoneError{}, expectedWait: time.Hour, expectedFailures: 1, disabled: true},
+		{name: "Max Failures", failures: 2, maxFailures: 3, err: errors.New("boom"), expectedWait: 4 * time.Hour, expectedFailures: 3, disabled: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, alice := newTestState(t)
+			feed := addTestFeed(t, s, alice, "Example", "https://example.com/feed.xml")
+
+			opts := scrapeOptions{interval: time.Hour, maxFailures: cmp.Or(tc.maxFailures, defaultMaxFailures)}
+			for range tc.failures {
+				feed = recordTestFailure(t, s, feed)
+			}
+
+			start := time.Now()
+			handleScrapeError(context.Background(), s, feed, tc.err, opts)
+
+			feed, err := s.Db.GetFeed(context.Background(), feed.Url)
+			if err != nil {
+				t.Fatalf("get feed: %v", err)
+			}
+
+			if feed.ConsecutiveFailures != tc.expectedFailures {
+				t.Errorf("expected %d failures, got %d", tc.expectedFailures, feed.ConsecutiveFailures)
+			}
+			if feed.DisabledAt.Valid != tc.disabled {
+				t.Errorf("expected disabled %v, got %v", tc.disabled, feed.DisabledAt.Valid)
+			}
+
+			wait := feed.NextFetchAt.Time.Sub(start)
+			if wait < tc.expectedWait || wait > tc.expectedWait+time.Minute {
+				t.Errorf("expected the next fetch in %v, got %v", tc.expectedWait, wait)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestScrapeFeedKeepsIntervalWhenLearningFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<rss><channel><title>Example</title><item><title>Post</title><link>https://example.com/post</link></item></channel></rss>`))
+	}))
+	defer server.Close()
+
+	s, alice := newTestState(t)
+	feed := addTestFeed(t, s, alice, "Example", server.URL)
+	s.Db = publishDatesFailing{s.Db}
+
+	opts := scrapeOptions{interval: 2 * time.Hour, maxFailures: defaultMaxFailures}
+	if _, err := scrapeFeed(context.Background(), s, feed, opts); err != nil {
+		t.Fatalf("expected the scrape to succeed, got %v", err)
+	}
+
+	feed, err := s.Db.GetFeed(context.Background(), feed.Url)
+	if err != nil {
+		t.Fatalf("get feed: %v", err)
+	}
+	if !feed.LastFetchedAt.Valid || feed.ConsecutiveFailures != 0 {
+		t.Errorf("expected the feed marked fetched without a failure, got fetched %v with %d failures", feed.LastFetchedAt.Valid, feed.ConsecutiveFailures)
+	}
+	if feed.PollIntervalSeconds.Int32 != int32(opts.interval.Seconds()) {
+		t.Errorf("expected the interval to stay %v, got %ds", opts.interval, feed.PollIntervalSeconds.Int32)
+	}
+	fmt.Printf("✅ Test Passed: scrape keeps its interval when learning fails\n")
+}
+
+
+func TestAggOnce(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		isErr  bool
+	}{
+		{name: "Every Feed Fetched", status: http.StatusOK},
+		{name: "A Feed Failed", status: http.StatusInternalServerError, isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`<rss><channel><title>Good</title><item><title>Post</title><link>https://example.com/post</link></item></channel></rss>`))
+			}))
+			defer good.Close()
+
+			other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`<rss><channel><title>Other</title></channel></rss>`))
+			}))
+			defer other.Close()
+
+			s, alice := newTestState(t)
+			addTestFeed(t, s, alice, "Good", good.URL)
+			addTestFeed(t, s, alice, "Other", other.URL)
+
+			err := Agg(s, Command{Name: "agg", Arguments: []string{"--once"}})
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected agg --once to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// feedPostTitles returns the titles of every post saved for a feed the
+// user follows
+func feedPostTitles(t *testing.T, s *State, user database.User, feed database.Feed) []string {
+	t.Helper()
+
+	posts, err := s.Db.GetPosts(context.Background(), database.GetPostsParams{
+		UserID:   user.ID,
+		FeedID:   uuid.NullUUID{UUID: feed.ID, Valid: true},
+		PageSize: 100,
+	})
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+
+	var titles []string
+	for _, post := range posts {
+		titles = append(titles, post.Title)
+	}
+
+	return titles
+}
+
+
+// recordTestFailure counts one more failed scrape for the feed
+func recordTestFailure(t *testing.T, s *State, feed database.Feed) database.Feed {
+	t.Helper()
+
+	feed, err := s.Db.RecordFeedFailure(context.Background(), database.RecordFeedFailureParams{
+		LastError:   nullString("boom"),
+		MaxFailures: defaultMaxFailures,
+		ID:          feed.ID,
+	})
+	if err != nil {
+		t.Fatalf("record feed failure: %v", err)
+	}
+
+	return feed
+}
+
+
+// publishDatesFailing is a store whose post history can't be read
+type publishDatesFailing struct {
+	store.Store
+}
+
+
+func (s publishDatesFailing) GetRecentPublishDates(ctx context.Context, arg database.GetRecentPublishDatesParams) ([]time.Time, error) {
+	return nil, errors.New("database is down")
+}
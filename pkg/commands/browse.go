@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+const defaultBrowseLimit = 10
+
+
+/****** COMMANDS ******/
+
+// Browse shows the newest unread posts of the feeds the user follows,
+// optionally from one feed, within a date range and a page at a time
+func Browse(s *State, cmd Command, user database.User) error {
+	flags := flag.NewFlagSet("browse", flag.ContinueOnError)
+	feedFilter := flags.String("feed", "", "only show posts from this feed, by url or name")
+	since := flags.String("since", "", "only show posts published since this date, time or duration ago")
+	until := flags.String("until", "", "only show posts published before this date, time or duration ago")
+	page := flags.Int("page", 1, "page of posts to show")
+	cursor := flags.String("cursor", "", "show the posts after this cursor from a previous page")
+	all := flags.Bool("all", false, "include posts you have already read")
+
+	args, err := parseArgs(flags, cmd.Arguments)
+	if err != nil {
+		return fmt.Errorf("parse arguments: %w", err)
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("usage: browse [limit] [--all] [--feed url|name] [--since T] [--until T] [--page N | --cursor C]")
+	}
+
+	limit := defaultBrowseLimit
+	if len(args) == 1 {
+		limit, err = strconv.Atoi(args[0])
+		if err != nil || limit < 1 {
+			return fmt.Errorf("limit must be a positive number, got %q", args[0])
+		}
+		if limit > math.MaxInt32 {
+			return fmt.Errorf("limit must be at most %d, got %d", math.MaxInt32, limit)
+		}
+	}
+
+	if *page < 1 {
+		return fmt.Errorf("page must be a positive number, got %d", *page)
+	}
+	// the offset is a 32 bit number in the queries
+	if *page-1 > math.MaxInt32/limit {
+		return fmt.Errorf("page must be at most %d with %d posts a page, got %d", math.MaxInt32/limit+1, limit, *page)
+	}
+	if *page > 1 && *cursor != "" {
+		return fmt.Errorf("use either --page or --cursor, not both")
+	}
+
+	ctx := context.Background()
+
+	params := database.GetPostsParams{
+		UserID:     user.ID,
+		UnreadOnly: !*all,
+		PageSize:   int32(limit),
+		PageOffset: int32((*page - 1) * limit),
+	}
+
+	if *feedFilter != "" {
+		feed, err := findFeed(ctx, s, *feedFilter)
+		if err != nil {
+			return err
+		}
+		params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+
+	if params.Since, err = parseBrowseTime(*since, false); err != nil {
+		return fmt.Errorf("parse --since: %w", err)
+	}
+	if params.Until, err = parseBrowseTime(*until, true); err != nil {
+		return fmt.Errorf("parse --until: %w", err)
+	}
+
+	if *cursor != "" {
+		publishedAt, id, err := decodeCursor(*cursor)
+		if err != nil {
+			return err
+		}
+		params.BeforePublishedAt = sql.NullTime{Time: publishedAt, Valid: true}
+		params.BeforeID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	posts, err := s.Db.GetPosts(ctx, params)
+	if err != nil {
+		return fmt.Errorf("get posts: %w", err)
+	}
+
+	if len(posts) == 0 {
+		if params.UnreadOnly {
+			fmt.Println("No unread posts")
+		} else {
+			fmt.Println("No posts")
+		}
+		return nil
+	}
+
+	for _, post := range posts {
+		fmt.Printf("*** %s: %s\n", post.Title, post.Url)
+		if post.ReadAt.Valid {
+			fmt.Printf("ID: %s (read)\n", post.ID)
+		} else {
+			fmt.Printf("ID: %s\n", post.ID)
+		}
+		fmt.Print("Description: \n")
+		fmt.Printf("%s\n\n", post.Description.String)
+	}
+
+	// a full page means there may be more
+	if len(posts) == limit {
+		fmt.Printf("More posts: --cursor %s\n", encodeCursor(posts[len(posts)-1]))
+	}
+
+	return nil
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// parseBrowseTime reads a date, a date and time in the local zone, an
+// RFC 3339 timestamp or a duration ago like 48h. A bare date used as an
+// upper bound includes that whole day
+func parseBrowseTime(value string, isUpperBound bool) (sql.NullTime, error) {
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+
+	if ago, err := time.ParseDuration(value); err == nil {
+		return sql.NullTime{Time: time.Now().Add(-ago).UTC(), Valid: true}, nil
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		if isUpperBound {
+			t = t.AddDate(0, 0, 1)
+		}
+		return sql.NullTime{Time: t.UTC(), Valid: true}, nil
+	}
+
+	for _, layout := range []string{time.DateTime, time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return sql.NullTime{Time: t.UTC(), Valid: true}, nil
+		}
+	}
+
+	return sql.NullTime{}, fmt.Errorf("%q is not a date, time or duration", value)
+}
+
+
+// encodeCursor points just past post, by publish date and then id like
+// the query orders them
+func encodeCursor(post database.GetPostsRow) string {
+	raw := post.PublishedAt.Format(time.RFC3339Nano) + "|" + post.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+
+func decodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	publishedAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.UUID{}, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, publishedAt)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, fmt.Errorf("invalid cursor %q: %w", cursor, err)
+	}
+
+	postID, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, fmt.Errorf("invalid cursor %q: %w", cursor, err)
+	}
+
+	return t, postID, nil
+}
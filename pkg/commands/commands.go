@@ -3,13 +3,13 @@ package commands
 import (
 	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"time"
 	"github.com/OriElbaz/gatorcli/internal/config"
 	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/internal/store"
 	"github.com/google/uuid"
-	"github.com/OriElbaz/gatorcli/pkg/rss"
-	"strconv"
 )
 
 
@@ -37,8 +37,10 @@ func (c *Commands) register(name string, f func(*State, Command) error) error {
 
 /***** STRUCTS *****/
 type State struct {
-	Db  *database.Queries
-	Cfg *config.Config
+	Db     store.Store
+	Conn   *sql.DB
+	Engine store.Engine
+	Cfg    *config.Config
 }
 
 
@@ -149,6 +151,9 @@ func Users(s *State, cmd Command) error {
 
 
 func AddFeed(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) != 2 {
+		return fmt.Errorf("usage: addfeed <name> <url>")
+	}
 
 	feedName := cmd.Arguments[0]
 	feedURL := cmd.Arguments[1]
@@ -182,6 +187,17 @@ func AddFeed(s *State, cmd Command, user database.User) error {
 
 
 func Feeds(s *State, cmd Command) error {
+	flags := flag.NewFlagSet("feeds", flag.ContinueOnError)
+	broken := flags.Bool("broken", false, "only show failing and disabled feeds")
+
+	if _, err := parseArgs(flags, cmd.Arguments); err != nil {
+		return fmt.Errorf("parse arguments: %w", err)
+	}
+
+	if *broken {
+		return brokenFeeds(s)
+	}
+
 	feeds, err := s.Db.ListFeeds(context.Background())
 	if err != nil {
 		return fmt.Errorf("list feeds: %w", err)
@@ -198,7 +214,33 @@ func Feeds(s *State, cmd Command) error {
 }
 
 
+// Feed groups the commands that act on a single feed by url
+func Feed(s *State, cmd Command) error {
+	if len(cmd.Arguments) < 2 {
+		return fmt.Errorf("usage: feed enable <url> | feed history <url|name> [--limit N]")
+	}
+
+	subcommand, args := cmd.Arguments[0], cmd.Arguments[1:]
+
+	switch subcommand {
+	case "enable":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: feed enable <url>")
+		}
+		return enableFeed(s, args[0])
+	case "history":
+		return feedHistory(s, args)
+	default:
+		return fmt.Errorf("unknown feed command %q", subcommand)
+	}
+}
+
+
 func Follow(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: follow <url>")
+	}
+
 	urlToAdd := sql.NullString{
 		String: cmd.Arguments[0],
 		Valid: true,
@@ -226,7 +268,7 @@ func Following(s *State, cmd Command, user database.User) error {
 	}
 
 	for _, feed := range feedFollows {
-		fmt.Printf("- %s\n", feed.FeedName)
+		fmt.Printf("- %s (%d unread)\n", feed.FeedName, feed.UnreadCount)
 	}
 
 	return nil
@@ -234,6 +276,10 @@ func Following(s *State, cmd Command, user database.User) error {
 
 
 func Unfollow(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: unfollow <url>")
+	}
+
 	unfollowUrl := sql.NullString{
 		String: cmd.Arguments[0],
 		Valid: true,
@@ -258,109 +304,55 @@ func Unfollow(s *State, cmd Command, user database.User) error {
 }
 
 
-func Agg(s *State, cmd Command) error {
-	timeString := cmd.Arguments[0]
-	timeBetweenRequests, err := time.ParseDuration(timeString)
-	if err != nil {
-		return fmt.Errorf("parse duration: %w", err)
-	}
-
-	ticker := time.NewTicker(timeBetweenRequests)
-	for ; ; <-ticker.C {
-		err := scrapeFeeds(s)
-		if err != nil {
-			fmt.Printf("[%s] ERROR: %v\n", time.Now().Format("15:04:05"), err)
-            continue
-		}
-	}
-}
-
-
-func scrapeFeeds(s *State) error {
-	feedToFetch, err := s.Db.GetNextFeedToFetch(context.Background())
-	if err != nil {
-		return fmt.Errorf("get next feed to fetchL %w", err)
-	}
-
-	feed, err := rss.FetchFeed(context.Background(), feedToFetch.Url.String)
+/** HELPER FUNCTIONS **/
+func brokenFeeds(s *State) error {
+	feeds, err := s.Db.ListBrokenFeeds(context.Background())
 	if err != nil {
-		return fmt.Errorf("fetch feed: %w", err)
+		return fmt.Errorf("list broken feeds: %w", err)
 	}
 
-	if err := s.Db.MarkFetched(context.Background(), feedToFetch.ID); err != nil {
-		return fmt.Errorf("mark fetched: %w", err)
+	if len(feeds) == 0 {
+		fmt.Println("No broken feeds")
+		return nil
 	}
 
-	params := database.CreatePostParams{}
-
-	fmt.Printf("***** %s *****\n", feed.Channel.Title)
-
-	for _, item := range feed.Channel.Item {
-		
-		description := sql.NullString{
-		String: item.Description,
-		Valid: true,
-		}
-
-		publishedTime, err := time.Parse("Mon, 02 Jan 2006 15:04:05 MST", item.PubDate)
-		if err != nil {
-			return fmt.Errorf("parse pubdate time: %w", err)
-		}
-
-		params = database.CreatePostParams{
-			ID: uuid.New(),
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-			Title: item.Title,
-			Url: item.Link,
-			Description: description,
-			PublishedAt: publishedTime,
-			FeedID: feedToFetch.ID,
-		}
-
-		if _, err = s.Db.CreatePost(context.Background(), params); err != nil {
-		return fmt.Errorf("*IGNORE* create post: %w", err)
+	for _, feed := range feeds {
+		status := "failing"
+		if feed.DisabledAt.Valid {
+			status = "disabled since " + feed.DisabledAt.Time.Format(time.DateTime)
 		}
 
-		fmt.Printf("Created Post: %s\n", item.Title)
+		fmt.Printf("== %s (%s) ==\n", feed.Name, status)
+		fmt.Printf("- url: %s\n", feed.Url.String)
+		fmt.Printf("- consecutive failures: %d\n", feed.ConsecutiveFailures)
+		fmt.Printf("- last error: %s\n", feed.LastError.String)
 	}
 
-	
-
 	return nil
-
 }
 
 
-func Browse(s *State, cmd Command, user database.User) error {
-	var limit int64
-	if len(cmd.Arguments) > 0 {
-		limit, _ = strconv.ParseInt(cmd.Arguments[0], 10, 64);
-	}
-
-	params := database.GetPostsParams{
-		UserID: user.ID,
-		Limit: int32(limit),
+func enableFeed(s *State, feedURL string) error {
+	urlToEnable := sql.NullString{
+		String: feedURL,
+		Valid: true,
 	}
 
-	posts, err := s.Db.GetPosts(context.Background(), params)
+	updated, err := s.Db.EnableFeed(context.Background(), urlToEnable)
 	if err != nil {
-		return fmt.Errorf("get posts: %w", err)
+		return fmt.Errorf("enable feed: %w", err)
 	}
 
-	for _, post := range posts {
-		fmt.Printf("*** %s: %s\n", post.Title, post.Url)
-		fmt.Print("Description: \n")
-		fmt.Printf("%s\n\n", post.Description.String)
+	if updated == 0 {
+		return fmt.Errorf("no feed with url %s", feedURL)
 	}
 
+	fmt.Printf("Enabled %s successfully\n", feedURL)
 	return nil
 }
 
 
 
-
-/** HELPER FUNCTIONS **/
 func createFeedFollowHelper(s *State, userId uuid.UUID, feedId uuid.UUID) (database.CreateFeedFollowRow, error) {
 	params := database.CreateFeedFollowParams{
 		ID: uuid.New(),
@@ -376,4 +368,25 @@ func createFeedFollowHelper(s *State, userId uuid.UUID, feedId uuid.UUID) (datab
 	}
 
 	return feedFollow, nil
-}
\ No newline at end of file
+}
+
+
+// parseArgs parses flags wherever they appear and returns the positional
+// arguments in order, so "agg 1m --workers 4" works like "agg --workers 4 1m"
+func parseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
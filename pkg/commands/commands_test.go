@@ -0,0 +1,432 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/config"
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/internal/store"
+	"github.com/google/uuid"
+)
+
+
+// newTestState returns a State backed by the in-memory store with alice
+// registered. HOME points at a temp dir since logging in writes the config
+func newTestState(t *testing.T) (*State, database.User) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	s := &State{Db: store.NewMemory(), Cfg: &config.Config{}}
+
+	return s, addTestUser(t, s, "alice")
+}
+
+
+// newBrowseState has alice follow two of bob's feeds but not her own, all
+// three with posts
+func newBrowseState(t *testing.T) (*State, database.User) {
+	t.Helper()
+
+	s, alice := newTestState(t)
+	bob := addTestUser(t, s, "bob")
+
+	feedA := addTestFeed(t, s, alice, "Blog A", "https://example.com/a.xml")
+	feedB := addTestFeed(t, s, bob, "Blog B", "https://example.com/b.xml")
+	feedC := addTestFeed(t, s, bob, "Blog C", "https://example.com/c.xml")
+
+	for _, feed := range []database.Feed{feedB, feedC} {
+		if _, err := createFeedFollowHelper(s, alice.ID, feed.ID); err != nil {
+			t.Fatalf("follow feed: %v", err)
+		}
+	}
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	addTestPost(t, s, feedA, "a 0", day.Add(13*time.Hour))
+	addTestPost(t, s, feedB, "b old", day.AddDate(0, -1, 0).Add(9*time.Hour))
+	addTestPost(t, s, feedB, "b 0", day.Add(10*time.Hour))
+	addTestPost(t, s, feedB, "b 1", day.Add(11*time.Hour))
+	addTestPost(t, s, feedB, "b 2", day.Add(12*time.Hour))
+	addTestPost(t, s, feedC, "c 0", day.Add(9*time.Hour+30*time.Minute))
+
+	return s, alice
+}
+
+
+// captureStdout returns what fn printed
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fnErr := fn()
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read stdout: %v", err)
+	}
+
+	return string(out), fnErr
+}
+
+
+func TestHandlerLogin(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		isErr bool
+	}{
+		{name: "Registered User", args: []string{"alice"}},
+		{name: "Unknown User", args: []string{"bob"}, isErr: true},
+		{name: "No Arguments", args: nil, isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newTestState(t)
+
+			_, err := captureStdout(t, func() error {
+				return HandlerLogin(s, Command{Name: "login", Arguments: tc.args})
+			})
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %v", tc.args)
+				}
+				if s.Cfg.CurrentUserName != "" {
+					t.Errorf("expected no current user, got %q", s.Cfg.CurrentUserName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			cfg, err := config.Read()
+			if err != nil {
+				t.Fatalf("read config: %v", err)
+			}
+			if cfg.CurrentUserName != tc.args[0] {
+				t.Errorf("expected %q saved as the current user, got %q", tc.args[0], cfg.CurrentUserName)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestAddFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		args     []string
+		isErr    bool
+	}{
+		{name: "New Feed", args: []string{"Go Blog", "https://go.dev/blog/feed.atom"}},
+		{
+			name:     "Duplicate URL",
+			existing: []string{"https://go.dev/blog/feed.atom"},
+			args:     []string{"Go Blog", "https://go.dev/blog/feed.atom"},
+			isErr:    true,
+		},
+		{name: "Missing URL", args: []string{"Go Blog"}, isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, user := newTestState(t)
+			for _, url := range tc.existing {
+				addTestFeed(t, s, user, "Example", url)
+			}
+
+			_, err := captureStdout(t, func() error {
+				return AddFeed(s, Command{Name: "addfeed", Arguments: tc.args}, user)
+			})
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %v", tc.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			feed, err := s.Db.GetFeed(context.Background(), sql.NullString{String: tc.args[1], Valid: true})
+			if err != nil {
+				t.Fatalf("get feed: %v", err)
+			}
+			if feed.Name != tc.args[0] || feed.UserID != user.ID {
+				t.Errorf("expected %q added by alice, got %q", tc.args[0], feed.Name)
+			}
+
+			follows, err := s.Db.GetFeedFollowsForUser(context.Background(), user.ID)
+			if err != nil {
+				t.Fatalf("get feed follows: %v", err)
+			}
+			if len(follows) != 1 || follows[0].FeedID != feed.ID {
+				t.Errorf("expected alice to follow the new feed, got %d follows", len(follows))
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestFollow(t *testing.T) {
+	tests := []struct {
+		name          string
+		alreadyFollow bool
+		args          []string
+		isErr         bool
+	}{
+		{name: "Existing Feed", args: []string{"https://example.com/feed.xml"}},
+		{name: "Already Following", alreadyFollow: true, args: []string{"https://example.com/feed.xml"}, isErr: true},
+		{name: "Unknown Feed", args: []string{"https://example.com/missing.xml"}, isErr: true},
+		{name: "No Arguments", args: nil, isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, user := newTestState(t)
+			feed := addTestFeed(t, s, user, "Example", "https://example.com/feed.xml")
+			if tc.alreadyFollow {
+				if _, err := createFeedFollowHelper(s, user.ID, feed.ID); err != nil {
+					t.Fatalf("follow feed: %v", err)
+				}
+			}
+
+			_, err := captureStdout(t, func() error {
+				return Follow(s, Command{Name: "follow", Arguments: tc.args}, user)
+			})
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %v", tc.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			follows, err := s.Db.GetFeedFollowsForUser(context.Background(), user.ID)
+			if err != nil {
+				t.Fatalf("get feed follows: %v", err)
+			}
+			if len(follows) != 1 || follows[0].FeedName != feed.Name {
+				t.Errorf("expected alice to follow %q, got %d follows", feed.Name, len(follows))
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestUnfollow(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		isErr bool
+	}{
+		{name: "Followed Feed", args: []string{"https://example.com/feed.xml"}},
+		{name: "Unknown Feed", args: []string{"https://example.com/missing.xml"}, isErr: true},
+		{name: "No Arguments", args: nil, isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, user := newTestState(t)
+			feed := addTestFeed(t, s, user, "Example", "https://example.com/feed.xml")
+			if _, err := createFeedFollowHelper(s, user.ID, feed.ID); err != nil {
+				t.Fatalf("follow feed: %v", err)
+			}
+
+			_, err := captureStdout(t, func() error {
+				return Unfollow(s, Command{Name: "unfollow", Arguments: tc.args}, user)
+			})
+
+			follows, followsErr := s.Db.GetFeedFollowsForUser(context.Background(), user.ID)
+			if followsErr != nil {
+				t.Fatalf("get feed follows: %v", followsErr)
+			}
+
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %v", tc.args)
+				}
+				if len(follows) != 1 {
+					t.Errorf("expected the follow to be kept, got %d follows", len(follows))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(follows) != 0 {
+				t.Errorf("expected no follows left, got %d", len(follows))
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestBrowse(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedPosts []string
+		isErr         bool
+	}{
+		{name: "Followed Feeds Only", args: []string{"10"}, expectedPosts: []string{"b 2", "b 1", "b 0", "c 0", "b old"}},
+		{name: "Default Limit", args: nil, expectedPosts: []string{"b 2", "b 1", "b 0", "c 0", "b old"}},
+		{name: "Limit", args: []string{"2"}, expectedPosts: []string{"b 2", "b 1"}},
+		{name: "Second Page", args: []string{"2", "--page", "2"}, expectedPosts: []string{"b 0", "c 0"}},
+		{name: "Feed By URL", args: []string{"--feed", "https://example.com/c.xml"}, expectedPosts: []string{"c 0"}},
+		{name: "Feed By Name", args: []string{"--feed", "Blog B"}, expectedPosts: []string{"b 2", "b 1", "b 0", "b old"}},
+		{name: "Since", args: []string{"--since", "2026-10-01"}, expectedPosts: []string{"b 2", "b 1", "b 0", "c 0"}},
+		{name: "Until", args: []string{"--until", "2026-09-30"}, expectedPosts: []string{"b old"}},
+		{name: "Zero Limit", args: []string{"0"}, isErr: true},
+		{name: "Limit Too Large", args: []string{"4294967297"}, isErr: true},
+		{name: "Page Too Far", args: []string{"1000", "--page", "4294967297"}, isErr: true},
+		{name: "Page And Cursor", args: []string{"--page", "2", "--cursor", "abc"}, isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, alice := newBrowseState(t)
+
+			out, err := captureStdout(t, func() error {
+				return Browse(s, Command{Name: "browse", Arguments: tc.args}, alice)
+			})
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %v", tc.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			titles := browsedTitles(out)
+			if strings.Join(titles, ", ") != strings.Join(tc.expectedPosts, ", ") {
+				t.Errorf("expected %v, got %v", tc.expectedPosts, titles)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestBrowseCursor(t *testing.T) {
+	s, alice := newBrowseState(t)
+
+	var titles []string
+	args := []string{"2"}
+
+	for range 5 {
+		out, err := captureStdout(t, func() error {
+			return Browse(s, Command{Name: "browse", Arguments: args}, alice)
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		titles = append(titles, browsedTitles(out)...)
+
+		_, cursor, ok := strings.Cut(out, "More posts: --cursor ")
+		if !ok {
+			break
+		}
+		args = []string{"2", "--cursor", strings.TrimSpace(cursor)}
+	}
+
+	expected := []string{"b 2", "b 1", "b 0", "c 0", "b old"}
+	if strings.Join(titles, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("expected %v across pages, got %v", expected, titles)
+	}
+	fmt.Printf("✅ Test Passed: browse cursor\n")
+}
+
+
+/** HELPER FUNCTIONS **/
+
+func addTestUser(t *testing.T, s *State, name string) database.User {
+	t.Helper()
+
+	user, err := s.Db.CreateUser(context.Background(), database.CreateUserParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Name:      sql.NullString{String: name, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	return user
+}
+
+
+func addTestFeed(t *testing.T, s *State, user database.User, name string, url string) database.Feed {
+	t.Helper()
+
+	feed, err := s.Db.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Name:      name,
+		Url:       sql.NullString{String: url, Valid: true},
+		UserID:    user.ID,
+	})
+	if err != nil {
+		t.Fatalf("create feed: %v", err)
+	}
+
+	return feed
+}
+
+
+func addTestPost(t *testing.T, s *State, feed database.Feed, title string, publishedAt time.Time) {
+	t.Helper()
+
+	_, err := s.Db.CreatePost(context.Background(), database.CreatePostParams{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		Title:       title,
+		Url:         feed.Url.String + "#" + title,
+		PublishedAt: publishedAt,
+		FeedID:      feed.ID,
+	})
+	if err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+}
+
+
+// browsedTitles pulls the post titles out of browse output
+func browsedTitles(out string) []string {
+	var titles []string
+	for line := range strings.Lines(out) {
+		if title, ok := strings.CutPrefix(line, "*** "); ok {
+			titles = append(titles, strings.SplitN(title, ":", 2)[0])
+		}
+	}
+
+	return titles
+}
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+)
+
+
+/****** COMMANDS ******/
+
+// Fetch runs the agg scrape pipeline right away for one feed, given by url
+// or name, or for every feed the user follows
+func Fetch(s *State, cmd Command, user database.User) error {
+	flags := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	allFollowed := flags.Bool("all-followed", false, "fetch every feed you follow")
+
+	args, err := parseArgs(flags, cmd.Arguments)
+	if err != nil {
+		return fmt.Errorf("parse arguments: %w", err)
+	}
+	validArgs := (*allFollowed && len(args) == 0) || (!*allFollowed && len(args) == 1)
+	if !validArgs {
+		return fmt.Errorf("usage: fetch <url|name> | fetch --all-followed")
+	}
+
+	ctx := context.Background()
+
+	var feeds []database.Feed
+	if *allFollowed {
+		feeds, err = s.Db.GetFollowedFeeds(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("get followed feeds: %w", err)
+		}
+	} else {
+		feed, err := findFeed(ctx, s, args[0])
+		if err != nil {
+			return err
+		}
+		feeds = []database.Feed{feed}
+	}
+
+	opts := scrapeOptions{
+		interval:    defaultPollInterval,
+		maxFailures: defaultMaxFailures,
+		verbose:     true,
+	}
+
+	failed := 0
+	for _, feed := range feeds {
+		if err := fetchNow(ctx, s, feed, opts); err != nil {
+			feedLogger(feed).Error("fetch feed", "error", err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d feeds failed", failed, len(feeds))
+	}
+
+	return nil
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// findFeed looks a feed up by url first, then by name
+func findFeed(ctx context.Context, s *State, urlOrName string) (database.Feed, error) {
+	feedURL := sql.NullString{
+		String: urlOrName,
+		Valid: true,
+	}
+
+	feed, err := s.Db.GetFeed(ctx, feedURL)
+	if err == nil {
+		return feed, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.Feed{}, fmt.Errorf("get feed: %w", err)
+	}
+
+	feeds, err := s.Db.GetFeedsByName(ctx, urlOrName)
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("get feeds by name: %w", err)
+	}
+
+	switch len(feeds) {
+	case 0:
+		return database.Feed{}, fmt.Errorf("no feed with url or name %q", urlOrName)
+	case 1:
+		return feeds[0], nil
+	default:
+		return database.Feed{}, fmt.Errorf("%d feeds are named %q, use the url instead", len(feeds), urlOrName)
+	}
+}
+
+
+// fetchNow claims a single feed like agg would, so it never races a
+// running aggregator, and scrapes it
+func fetchNow(ctx context.Context, s *State, feed database.Feed, opts scrapeOptions) error {
+	if feed.DisabledAt.Valid {
+		return fmt.Errorf("feed is disabled, re-enable it with: gator feed enable %s", feed.Url.String)
+	}
+
+	params := database.ClaimFeedParams{
+		LeaseSeconds: int32(claimLease.Seconds()),
+		ID:           feed.ID,
+	}
+
+	claimed, err := s.Db.ClaimFeed(ctx, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("feed is being fetched by an aggregator right now")
+	}
+	if err != nil {
+		return fmt.Errorf("claim feed: %w", err)
+	}
+
+	defer func() {
+		if err := s.Db.ReleaseFeedClaim(ctx, releaseParams(claimed)); err != nil {
+			feedLogger(claimed).Error("release feed claim", "error", err)
+		}
+	}()
+
+	if _, err := scrapeFeed(ctx, s, claimed, opts); err != nil {
+		handleScrapeError(ctx, s, claimed, err, opts)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+)
+
+
+func TestFetch(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		expectedFetched []string
+		isErr           bool
+	}{
+		{name: "By URL", args: []string{"/followed.xml"}, expectedFetched: []string{"/followed.xml"}},
+		{name: "By Name", args: []string{"Followed"}, expectedFetched: []string{"/followed.xml"}},
+		{name: "Feed Not Followed", args: []string{"Not Followed"}, expectedFetched: []string{"/not-followed.xml"}},
+		{name: "All Followed", args: []string{"--all-followed"}, expectedFetched: []string{"/followed.xml", "/other.xml"}},
+		{name: "Failing Feed", args: []string{"Broken"}, expectedFetched: []string{"/broken.xml"}, isErr: true},
+		{name: "Unknown Feed", args: []string{"Missing"}, isErr: true},
+		{name: "URL And All Followed", args: []string{"--all-followed", "Followed"}, isErr: true},
+		{name: "No Arguments", args: nil, isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, alice, server := newFetchState(t)
+
+			args := slices.Clone(tc.args)
+			for i, arg := range args {
+				if strings.HasPrefix(arg, "/") {
+					args[i] = server.URL + arg
+				}
+			}
+
+			captureLog(t)
+			err := Fetch(s, Command{Name: "fetch", Arguments: args}, alice)
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %v", tc.args)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if fetched := server.fetched(); !slices.Equal(fetched, tc.expectedFetched) {
+				t.Errorf("expected %v fetched, got %v", tc.expectedFetched, fetched)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestFetchItemOutput(t *testing.T) {
+	s, alice, _ := newFetchState(t)
+
+	out := captureLog(t)
+	if err := Fetch(s, Command{Name: "fetch", Arguments: []string{"Followed"}}, alice); err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	for _, title := range []string{"Post 1", "Post 2"} {
+		if !loggedItem(out.String(), "post created", title) {
+			t.Errorf("expected %q logged as created, got:\n%s", title, out)
+		}
+	}
+
+	out.Reset()
+	if err := Fetch(s, Command{Name: "fetch", Arguments: []string{"Followed"}}, alice); err != nil {
+		t.Fatalf("second fetch: %v", err)
+	}
+	for _, title := range []string{"Post 1", "Post 2"} {
+		if !loggedItem(out.String(), "post skipped (duplicate)", title) {
+			t.Errorf("expected %q logged as a duplicate, got:\n%s", title, out)
+		}
+	}
+	if !strings.Contains(out.String(), "inserted=0 duplicates=2") {
+		t.Errorf("expected the summary to count two duplicates, got:\n%s", out)
+	}
+	fmt.Printf("✅ Test Passed: fetch item output\n")
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// feedServer serves a two post feed on every path but /broken.xml and
+// remembers which paths were fetched
+type feedServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	paths []string
+}
+
+
+func (f *feedServer) fetched() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Sorted(slices.Values(f.paths))
+}
+
+
+// newFetchState has alice follow two of bob's feeds on a test server, but
+// not his other two, one of which is broken
+func newFetchState(t *testing.T) (*State, database.User, *feedServer) {
+	t.Helper()
+
+	server := &feedServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		server.paths = append(server.paths, r.URL.Path)
+		server.mu.Unlock()
+
+		if r.URL.Path == "/broken.xml" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		fmt.Fprintf(w, `<rss><channel><title>%[1]s</title>
+			<item><title>Post 1</title><link>https://example.com%[1]s/1</link><pubDate>Thu, 01 Oct 2026 09:00:00 GMT</pubDate></item>
+			<item><title>Post 2</title><link>https://example.com%[1]s/2</link><pubDate>Thu, 01 Oct 2026 10:00:00 GMT</pubDate></item>
+		</channel></rss>`, r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+
+	s, alice := newTestState(t)
+	bob := addTestUser(t, s, "bob")
+
+	for _, name := range []string{"Followed", "Other"} {
+		feed := addTestFeed(t, s, bob, name, server.URL+"/"+strings.ToLower(name)+".xml")
+		if _, err := createFeedFollowHelper(s, alice.ID, feed.ID); err != nil {
+			t.Fatalf("follow feed: %v", err)
+		}
+	}
+	addTestFeed(t, s, bob, "Not Followed", server.URL+"/not-followed.xml")
+	addTestFeed(t, s, bob, "Broken", server.URL+"/broken.xml")
+
+	return s, alice, server
+}
+
+
+// captureLog sends slog output to the returned buffer until the test ends
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(out, nil)))
+	t.Cleanup(func() { slog.SetDefault(logger) })
+
+	return out
+}
+
+
+// loggedItem reports whether a log line with msg was written for the post
+func loggedItem(out string, msg string, title string) bool {
+	for line := range strings.Lines(out) {
+		if strings.Contains(line, fmt.Sprintf("msg=%q", msg)) && strings.Contains(line, fmt.Sprintf("title=%q", title)) {
+			return true
+		}
+	}
+
+	return false
+}
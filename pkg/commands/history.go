@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/pkg/rss"
+	"github.com/google/uuid"
+)
+
+// defaultHistoryRetention is how long agg keeps feed_fetches rows
+const defaultHistoryRetention = 30 * 24 * time.Hour
+
+// pruneEvery is how often a long-running agg prunes feed_fetches
+const pruneEvery = time.Hour
+
+
+/** HELPER FUNCTIONS **/
+
+// recordFetch logs one scrape to feed_fetches. It runs even after ctx is
+// cancelled so interrupted fetches show up in the history too.
+func recordFetch(ctx context.Context, s *State, feedID uuid.UUID, start time.Time, feed *rss.RSSFeed, summary scrapeSummary, scrapeErr error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	status := feed.StatusCode
+	if scrapeErr != nil && status == 0 {
+		status = rss.StatusCode(scrapeErr)
+	}
+
+	params := database.CreateFeedFetchParams{
+		ID:            uuid.New(),
+		FeedID:        feedID,
+		StartedAt:     start,
+		DurationMs:    int32(time.Since(start).Milliseconds()),
+		HttpStatus:    sql.NullInt32{Int32: int32(status), Valid: status != 0},
+		Bytes:         sql.NullInt32{Int32: int32(feed.Bytes), Valid: feed.StatusCode != 0},
+		ItemsSeen:     int32(len(feed.Channel.Item)),
+		PostsInserted: int32(summary.count(outcomeInserted)),
+	}
+
+	if scrapeErr != nil {
+		params.Error = nullString(scrapeErr.Error())
+	}
+
+	if err := s.Db.CreateFeedFetch(ctx, params); err != nil {
+		dbErrors.WithLabelValues("create_feed_fetch").Inc()
+		slog.Error("record feed fetch", "feed_id", feedID, "error", err)
+	}
+}
+
+
+// pruneHistory deletes feed_fetches rows older than retention
+func pruneHistory(ctx context.Context, s *State, retention time.Duration) error {
+	pruned, err := s.Db.PruneFeedFetches(ctx, time.Now().Add(-retention))
+	if err != nil {
+		dbErrors.WithLabelValues("prune_feed_fetches").Inc()
+		return fmt.Errorf("prune feed fetches: %w", err)
+	}
+
+	if pruned > 0 {
+		slog.Info("pruned fetch history", "entries", pruned, "older_than", retention)
+	}
+
+	return nil
+}
+
+
+func feedHistory(s *State, args []string) error {
+	flags := flag.NewFlagSet("feed history", flag.ContinueOnError)
+	limit := flags.Int("limit", 20, "number of fetches to show")
+
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return fmt.Errorf("parse arguments: %w", err)
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: feed history <url|name> [--limit N]")
+	}
+	if *limit < 1 {
+		return fmt.Errorf("limit must be a positive number, got %d", *limit)
+	}
+	if *limit > math.MaxInt32 {
+		return fmt.Errorf("limit must be at most %d, got %d", math.MaxInt32, *limit)
+	}
+
+	ctx := context.Background()
+
+	feed, err := findFeed(ctx, s, args[0])
+	if err != nil {
+		return err
+	}
+
+	params := database.GetFeedFetchesParams{
+		FeedID: feed.ID,
+		Limit:  int32(*limit),
+	}
+
+	fetches, err := s.Db.GetFeedFetches(ctx, params)
+	if err != nil {
+		return fmt.Errorf("get feed fetches: %w", err)
+	}
+
+	fmt.Printf("== %s ==\n", feed.Name)
+	if len(fetches) == 0 {
+		fmt.Println("No fetches recorded")
+		return nil
+	}
+
+	for _, fetch := range fetches {
+		status := "---"
+		if fetch.HttpStatus.Valid {
+			status = fmt.Sprint(fetch.HttpStatus.Int32)
+		}
+
+		fmt.Printf("%s  %s  %6s  %8d bytes  %3d items  %3d new",
+			fetch.StartedAt.Local().Format(time.DateTime),
+			status,
+			(time.Duration(fetch.DurationMs) * time.Millisecond).String(),
+			fetch.Bytes.Int32,
+			fetch.ItemsSeen,
+			fetch.PostsInserted,
+		)
+
+		if fetch.Error.Valid {
+			fmt.Printf("  ERROR: %s", fetch.Error.String)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/pkg/rss"
+	"github.com/google/uuid"
+)
+
+
+func TestRecordFetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		feed     *rss.RSSFeed
+		items    int
+		summary  scrapeSummary
+		err      error
+		expected database.FeedFetch
+	}{
+		{
+			name: "Success",
+			feed:  &rss.RSSFeed{StatusCode: 200, Bytes: 1234},
+			items: 3,
+			summary: scrapeSummary{Items: []itemResult{
+				{Outcome: outcomeInserted},
+				{Outcome: outcomeInserted},
+				{Outcome: outcomeDuplicate},
+			}},
+			expected: database.FeedFetch{
+				HttpStatus:    sql.NullInt32{Int32: 200, Valid: true},
+				Bytes:         sql.NullInt32{Int32: 1234, Valid: true},
+				ItemsSeen:     3,
+				PostsInserted: 2,
+			},
+		},
+		{
+			name: "Not Modified",
+			feed: &rss.RSSFeed{StatusCode: 304, NotModified: true},
+			expected: database.FeedFetch{
+				HttpStatus: sql.NullInt32{Int32: 304, Valid: true},
+				Bytes:      sql.NullInt32{Int32: 0, Valid: true},
+			},
+		},
+		{
+			name: "Status From The Error",
+			feed: &rss.RSSFeed{},
+			err:  fmt.Errorf("fetch feed: %w", &rss.ServerError{URL: "https://example.com/feed.xml", StatusCode: 503}),
+			expected: database.FeedFetch{
+				HttpStatus: sql.NullInt32{Int32: 503, Valid: true},
+				Error:      sql.NullString{String: "fetch feed: https://example.com/feed.xml: server error (503)", Valid: true},
+			},
+		},
+		{
+			name: "No Response",
+			feed: &rss.RSSFeed{},
+			err:  errors.New("connection refused"),
+			expected: database.FeedFetch{
+				Error: sql.NullString{String: "connection refused", Valid: true},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, alice := newTestState(t)
+			feed := addTestFeed(t, s, alice, "Example", "https://example.com/feed.xml")
+
+			// an interrupted scrape is still recorded
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			tc.feed.Channel.Item = make([]rss.RSSItem, tc.items)
+			start := time.Now().Add(-time.Second)
+			recordFetch(ctx, s, feed.ID, start, tc.feed, tc.summary, tc.err)
+
+			fetches, err := s.Db.GetFeedFetches(context.Background(), database.GetFeedFetchesParams{FeedID: feed.ID, Limit: 10})
+			if err != nil {
+				t.Fatalf("get feed fetches: %v", err)
+			}
+			if len(fetches) != 1 {
+				t.Fatalf("expected 1 fetch recorded, got %d", len(fetches))
+			}
+
+			got := fetches[0]
+			if !got.StartedAt.Equal(start) || got.DurationMs < 1000 {
+				t.Errorf("expected a fetch started at %v taking a second, got %v taking %dms", start, got.StartedAt, got.DurationMs)
+			}
+
+			tc.expected.ID, tc.expected.FeedID, tc.expected.StartedAt, tc.expected.DurationMs = got.ID, feed.ID, got.StartedAt, got.DurationMs
+			if got != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, got)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestFeedHistory(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		expectedFetches int
+		expectedOutput  string
+		isErr           bool
+	}{
+		{name: "Default Limit", args: []string{"Example"}, expectedFetches: 3, expectedOutput: "ERROR: boom"},
+		{name: "By URL", args: []string{"https://example.com/feed.xml"}, expectedFetches: 3},
+		{name: "Limit", args: []string{"Example", "--limit", "2"}, expectedFetches: 2},
+		{name: "No Fetches", args: []string{"Quiet"}, expectedOutput: "No fetches recorded"},
+		{name: "Zero Limit", args: []string{"Example", "--limit", "0"}, isErr: true},
+		{name: "Limit Too Large", args: []string{"Example", "--limit", "4294967297"}, isErr: true},
+		{name: "Unknown Feed", args: []string{"Missing"}, isErr: true},
+		{name: "No Arguments", args: nil, isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, alice := newTestState(t)
+			feed := addTestFeed(t, s, alice, "Example", "https://example.com/feed.xml")
+			addTestFeed(t, s, alice, "Quiet", "https://example.com/quiet.xml")
+
+			now := time.Now()
+			addTestFetch(t, s, feed, now.Add(-3*time.Hour), nil)
+			addTestFetch(t, s, feed, now.Add(-2*time.Hour), errors.New("boom"))
+			addTestFetch(t, s, feed, now.Add(-time.Hour), nil)
+
+			out, err := captureStdout(t, func() error {
+				return feedHistory(s, tc.args)
+			})
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %v", tc.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if fetches := strings.Count(out, " bytes "); fetches != tc.expectedFetches {
+				t.Errorf("expected %d fetches listed, got %d:\n%s", tc.expectedFetches, fetches, out)
+			}
+			if !strings.Contains(out, tc.expectedOutput) {
+				t.Errorf("expected %q in the output, got:\n%s", tc.expectedOutput, out)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestPruneHistory(t *testing.T) {
+	s, alice := newTestState(t)
+	feed := addTestFeed(t, s, alice, "Example", "https://example.com/feed.xml")
+
+	now := time.Now()
+	addTestFetch(t, s, feed, now.Add(-40*24*time.Hour), nil)
+	addTestFetch(t, s, feed, now.Add(-31*24*time.Hour), nil)
+	addTestFetch(t, s, feed, now.Add(-29*24*time.Hour), nil)
+	addTestFetch(t, s, feed, now.Add(-time.Hour), nil)
+
+	if err := pruneHistory(context.Background(), s, defaultHistoryRetention); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fetches, err := s.Db.GetFeedFetches(context.Background(), database.GetFeedFetchesParams{FeedID: feed.ID, Limit: 10})
+	if err != nil {
+		t.Fatalf("get feed fetches: %v", err)
+	}
+	if len(fetches) != 2 {
+		t.Fatalf("expected the 2 fetches inside the retention kept, got %d", len(fetches))
+	}
+	for _, fetch := range fetches {
+		if now.Sub(fetch.StartedAt) > defaultHistoryRetention {
+			t.Errorf("expected fetches from %v pruned", fetch.StartedAt)
+		}
+	}
+	fmt.Printf("✅ Test Passed: prune history\n")
+}
+
+
+/** HELPER FUNCTIONS **/
+
+func addTestFetch(t *testing.T, s *State, feed database.Feed, startedAt time.Time, fetchErr error) {
+	t.Helper()
+
+	params := database.CreateFeedFetchParams{
+		ID:            uuid.New(),
+		FeedID:        feed.ID,
+		StartedAt:     startedAt,
+		DurationMs:    120,
+		HttpStatus:    sql.NullInt32{Int32: 200, Valid: fetchErr == nil},
+		Bytes:         sql.NullInt32{Int32: 2048, Valid: fetchErr == nil},
+		ItemsSeen:     5,
+		PostsInserted: 1,
+	}
+	if fetchErr != nil {
+		params.Error = nullString(fetchErr.Error())
+	}
+
+	if err := s.Db.CreateFeedFetch(context.Background(), params); err != nil {
+		t.Fatalf("create feed fetch: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+)
+
+
+// NewLogger builds the logger every command logs through. format is text
+// or json, level is debug, info, warn or error.
+func NewLogger(w io.Writer, level string, format string) (*slog.Logger, error) {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("parse log level %q: %w", level, err)
+	}
+
+	opts := &slog.HandlerOptions{Level: logLevel}
+
+	switch strings.ToLower(format) {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q, use text or json", format)
+	}
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// feedLogger tags every record with the feed it is about, so logs can be
+// grepped by feed
+func feedLogger(feed database.Feed) *slog.Logger {
+	return slog.With("feed_id", feed.ID, "feed_url", feed.Url.String)
+}
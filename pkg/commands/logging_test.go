@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		name         string
+		level        string
+		format       string
+		enabled      slog.Level
+		disabled     []slog.Level
+		expectedJSON bool
+		isErr        bool
+	}{
+		{name: "Debug Text", level: "debug", format: "text", enabled: slog.LevelDebug},
+		{name: "Info JSON", level: "info", format: "json", enabled: slog.LevelInfo, disabled: []slog.Level{slog.LevelDebug}, expectedJSON: true},
+		{name: "Upper Case", level: "WARN", format: "JSON", enabled: slog.LevelWarn, disabled: []slog.Level{slog.LevelDebug, slog.LevelInfo}, expectedJSON: true},
+		{name: "Error Only", level: "error", format: "text", enabled: slog.LevelError, disabled: []slog.Level{slog.LevelInfo, slog.LevelWarn}},
+		{name: "Unknown Level", level: "verbose", format: "text", isErr: true},
+		{name: "Unknown Format", level: "info", format: "yaml", isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			logger, err := NewLogger(out, tc.level, tc.format)
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for level %q and format %q", tc.level, tc.format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx := context.Background()
+			if !logger.Enabled(ctx, tc.enabled) {
+				t.Errorf("expected %v enabled", tc.enabled)
+			}
+			for _, level := range tc.disabled {
+				if logger.Enabled(ctx, level) {
+					t.Errorf("expected %v disabled", level)
+				}
+			}
+
+			logger.Log(ctx, tc.enabled, "hello", "feed", "example")
+			line := strings.TrimSpace(out.String())
+			if json.Valid([]byte(line)) != tc.expectedJSON {
+				t.Errorf("expected json %v, got %s", tc.expectedJSON, line)
+			}
+			if !strings.Contains(line, "hello") || !strings.Contains(line, "example") {
+				t.Errorf("expected the message and its attributes, got %s", line)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
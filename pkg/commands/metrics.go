@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/pkg/rss"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// overdueAfter is how long a feed may sit past its next_fetch_at before
+// it counts as overdue, which means agg is falling behind
+const overdueAfter = 10 * time.Minute
+
+
+// metrics are always collected, agg --metrics-addr only decides whether
+// they are served
+var (
+	metricsRegistry = prometheus.NewRegistry()
+
+	fetchesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gator_fetches_total",
+		Help: "Feed fetches by outcome.",
+	}, []string{"outcome"})
+
+	fetchDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gator_fetch_duration_seconds",
+		Help:    "Time taken to fetch, parse and save one feed.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	})
+
+	postsInserted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gator_posts_inserted_total",
+		Help: "New posts saved from fetched feeds.",
+	})
+
+	feedsDue = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gator_feeds_due",
+		Help: "Enabled feeds whose next fetch is due.",
+	})
+
+	feedsOverdue = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gator_feeds_overdue",
+		Help: "Enabled feeds that have been due for longer than 10 minutes.",
+	})
+
+	dbErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gator_db_errors_total",
+		Help: "Failed database calls made by the aggregator, by query.",
+	}, []string{"query"})
+
+	lastCycle = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gator_last_successful_cycle_timestamp_seconds",
+		Help: "Unix time of the last aggregator cycle that claimed feeds without error.",
+	})
+)
+
+
+func init() {
+	metricsRegistry.MustRegister(
+		fetchesTotal,
+		fetchDuration,
+		postsInserted,
+		feedsDue,
+		feedsOverdue,
+		dbErrors,
+		lastCycle,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// serveMetrics exposes /metrics on addr until ctx is cancelled. Listening
+// happens up front so a bad address fails agg right away.
+func serveMetrics(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("metrics server", "error", err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+
+		server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("serving metrics", "addr", listener.Addr().String(), "path", "/metrics")
+	return nil
+}
+
+
+// observeFetch records one scrape in the fetch metrics
+func observeFetch(start time.Time, feed *rss.RSSFeed, summary scrapeSummary, err error) {
+	fetchDuration.Observe(time.Since(start).Seconds())
+	fetchesTotal.WithLabelValues(fetchOutcome(feed, err)).Inc()
+	postsInserted.Add(float64(summary.count(outcomeInserted)))
+}
+
+
+// fetchOutcome names the result of a scrape, using the typed errors from
+// rss.FetchFeed so alerts can tell a dead feed from a slow one
+func fetchOutcome(feed *rss.RSSFeed, err error) string {
+	var (
+		gone        *rss.GoneError
+		notFound    *rss.NotFoundError
+		rateLimited *rss.RateLimitedError
+		serverErr   *rss.ServerError
+		parseErr    *rss.ParseError
+		timeout     *rss.TimeoutError
+	)
+
+	switch {
+	case err == nil && feed.NotModified:
+		return "not_modified"
+	case err == nil:
+		return "ok"
+	case errors.Is(err, context.Canceled):
+		return "interrupted"
+	case errors.As(err, &gone):
+		return "gone"
+	case errors.As(err, &notFound):
+		return "not_found"
+	case errors.As(err, &rateLimited):
+		return "rate_limited"
+	case errors.As(err, &serverErr):
+		return "server_error"
+	case errors.As(err, &parseErr):
+		return "parse_error"
+	case errors.As(err, &timeout):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
+
+
+// updateDueFeeds refreshes the due and overdue gauges
+func updateDueFeeds(ctx context.Context, s *State) error {
+	counts, err := s.Db.CountDueFeeds(ctx, int32(overdueAfter.Seconds()))
+	if err != nil {
+		dbErrors.WithLabelValues("count_due_feeds").Inc()
+		return fmt.Errorf("count due feeds: %w", err)
+	}
+
+	feedsDue.Set(float64(counts.Due))
+	feedsOverdue.Set(float64(counts.Overdue))
+
+	return nil
+}
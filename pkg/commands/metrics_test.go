@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/OriElbaz/gatorcli/pkg/rss"
+)
+
+
+func TestFetchOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		feed     *rss.RSSFeed
+		err      error
+		expected string
+	}{
+		{name: "OK", feed: &rss.RSSFeed{}, expected: "ok"},
+		{name: "Not Modified", feed: &rss.RSSFeed{NotModified: true}, expected: "not_modified"},
+		{
+			name:     "Interrupted Request",
+			err:      fmt.Errorf("fetch feed: %w", &url.Error{Op: "Get", URL: "https://example.com/feed.xml", Err: context.Canceled}),
+			expected: "interrupted",
+		},
+		{name: "Interrupted Save", err: fmt.Errorf("save posts: %w", context.Canceled), expected: "interrupted"},
+		{
+			name:     "Timeout",
+			err:      fmt.Errorf("fetch feed: %w", &rss.TimeoutError{Err: context.DeadlineExceeded}),
+			expected: "timeout",
+		},
+		{name: "Parse Error", err: fmt.Errorf("fetch feed: %w", &rss.ParseError{Err: errors.New("EOF")}), expected: "parse_error"},
+		{name: "Gone", err: &rss.GoneError{}, expected: "gone"},
+		{name: "Not Found", err: &rss.NotFoundError{}, expected: "not_found"},
+		{name: "Rate Limited", err: &rss.RateLimitedError{}, expected: "rate_limited"},
+		{name: "Server Error", err: fmt.Errorf("fetch feed: %w", &rss.ServerError{StatusCode: 502}), expected: "server_error"},
+		{name: "Other Error", err: errors.New("connection refused"), expected: "error"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			feed := tc.feed
+			if feed == nil {
+				feed = &rss.RSSFeed{}
+			}
+
+			if got := fetchOutcome(feed, tc.err); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
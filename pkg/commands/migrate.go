@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/migrate"
+	"github.com/pressly/goose/v3"
+)
+
+
+/****** COMMANDS ******/
+
+// Migrate applies, rolls back or lists the schema migrations embedded in
+// the binary
+func Migrate(s *State, cmd Command) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: migrate up|down|status")
+	}
+
+	ctx := context.Background()
+
+	switch cmd.Arguments[0] {
+	case "up":
+		results, err := migrate.Up(ctx, s.Conn, s.Engine)
+		for _, result := range results {
+			printMigration("Applied", result)
+		}
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Println("Database is up to date")
+		}
+		return nil
+	case "down":
+		result, err := migrate.Down(ctx, s.Conn, s.Engine)
+		if err != nil {
+			return err
+		}
+		printMigration("Rolled back", result)
+		return nil
+	case "status":
+		return migrationStatus(ctx, s)
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q, use up, down or status", cmd.Arguments[0])
+	}
+}
+
+
+/** HELPER FUNCTIONS **/
+
+func migrationStatus(ctx context.Context, s *State) error {
+	status, err := migrate.Status(ctx, s.Conn, s.Engine)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range status {
+		appliedAt := "pending"
+		if migration.State == goose.StateApplied {
+			appliedAt = migration.AppliedAt.Local().Format(time.DateTime)
+		}
+
+		fmt.Printf("%-20s %s\n", appliedAt, path.Base(migration.Source.Path))
+	}
+
+	return nil
+}
+
+
+func printMigration(verb string, result *goose.MigrationResult) {
+	if result == nil {
+		return
+	}
+
+	if result.Error != nil {
+		fmt.Printf("FAILED %s: %v\n", path.Base(result.Source.Path), result.Error)
+		return
+	}
+
+	fmt.Printf("%s %s in %s\n", verb, path.Base(result.Source.Path), result.Duration.Round(time.Millisecond))
+}
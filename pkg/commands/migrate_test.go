@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/OriElbaz/gatorcli/internal/store"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+
+func TestMigrate(t *testing.T) {
+	engine, dsn, err := store.ParseURL("sqlite://" + filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+
+	db, err := sql.Open(engine.Driver(), dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	s := &State{Db: store.New(engine, db), Conn: db, Engine: engine}
+
+	steps := []struct {
+		name            string
+		args            []string
+		expectedPending int
+		expectedOutput  string
+		isErr           bool
+	}{
+		{name: "Status Of A Fresh Database", args: []string{"status"}, expectedPending: 4, expectedOutput: "001_initial.sql"},
+		{name: "Up", args: []string{"up"}, expectedOutput: "Applied 004_post_stars.sql"},
+		{name: "Status After Up", args: []string{"status"}, expectedOutput: "004_post_stars.sql"},
+		{name: "Up Again", args: []string{"up"}, expectedOutput: "Database is up to date"},
+		{name: "Down", args: []string{"down"}, expectedOutput: "Rolled back 004_post_stars.sql"},
+		{name: "Status After Down", args: []string{"status"}, expectedPending: 1, expectedOutput: "004_post_stars.sql"},
+		{name: "Unknown Subcommand", args: []string{"sideways"}, isErr: true},
+		{name: "No Subcommand", args: nil, isErr: true},
+	}
+
+	// each step runs against the database the previous ones left behind
+	for _, step := range steps {
+		out, err := captureStdout(t, func() error {
+			return Migrate(s, Command{Name: "migrate", Arguments: step.args})
+		})
+		if step.isErr {
+			if err == nil {
+				t.Fatalf("%s: expected error for %v", step.name, step.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+
+		if pending := strings.Count(out, "pending"); pending != step.expectedPending {
+			t.Errorf("%s: expected %d pending migrations, got %d:\n%s", step.name, step.expectedPending, pending, out)
+		}
+		if !strings.Contains(out, step.expectedOutput) {
+			t.Errorf("%s: expected %q in the output, got:\n%s", step.name, step.expectedOutput, out)
+		}
+		fmt.Printf("✅ Test Passed: %s\n", step.name)
+	}
+}
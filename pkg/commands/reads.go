@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+
+/****** COMMANDS ******/
+
+// Read marks one post as read, or with --all every post of the feeds the
+// user follows, optionally only those of --feed
+func Read(s *State, cmd Command, user database.User) error {
+	flags := flag.NewFlagSet("read", flag.ContinueOnError)
+	all := flags.Bool("all", false, "mark every post of the feeds you follow as read")
+	feedFilter := flags.String("feed", "", "with --all, only mark the posts of this feed, by url or name")
+
+	args, err := parseArgs(flags, cmd.Arguments)
+	if err != nil {
+		return fmt.Errorf("parse arguments: %w", err)
+	}
+
+	ctx := context.Background()
+
+	if *all {
+		if len(args) != 0 {
+			return fmt.Errorf("usage: read <post-id> | read --all [--feed url|name]")
+		}
+		return readAll(ctx, s, user, *feedFilter)
+	}
+
+	if len(args) != 1 || *feedFilter != "" {
+		return fmt.Errorf("usage: read <post-id> | read --all [--feed url|name]")
+	}
+
+	post, err := findPost(ctx, s, args[0])
+	if err != nil {
+		return err
+	}
+
+	params := database.MarkPostReadParams{
+		UserID: user.ID,
+		PostID: post.ID,
+		ReadAt: time.Now(),
+	}
+
+	if err := s.Db.MarkPostRead(ctx, params); err != nil {
+		return fmt.Errorf("mark post read: %w", err)
+	}
+
+	fmt.Printf("Marked %q as read\n", post.Title)
+	return nil
+}
+
+
+// Unread marks a post as not read, so browse shows it again
+func Unread(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: unread <post-id>")
+	}
+
+	ctx := context.Background()
+
+	post, err := findPost(ctx, s, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+
+	params := database.MarkPostUnreadParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	}
+
+	if _, err := s.Db.MarkPostUnread(ctx, params); err != nil {
+		return fmt.Errorf("mark post unread: %w", err)
+	}
+
+	fmt.Printf("Marked %q as unread\n", post.Title)
+	return nil
+}
+
+
+/** HELPER FUNCTIONS **/
+
+func readAll(ctx context.Context, s *State, user database.User, feedFilter string) error {
+	params := database.MarkPostsReadParams{
+		ReadAt: time.Now(),
+		UserID: user.ID,
+	}
+
+	if feedFilter != "" {
+		feed, err := findFeed(ctx, s, feedFilter)
+		if err != nil {
+			return err
+		}
+		params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+
+	marked, err := s.Db.MarkPostsRead(ctx, params)
+	if err != nil {
+		return fmt.Errorf("mark posts read: %w", err)
+	}
+
+	fmt.Printf("Marked %d posts as read\n", marked)
+	return nil
+}
+
+
+func findPost(ctx context.Context, s *State, postID string) (database.GetPostRow, error) {
+	id, err := uuid.Parse(postID)
+	if err != nil {
+		return database.GetPostRow{}, fmt.Errorf("invalid post id %q", postID)
+	}
+
+	post, err := s.Db.GetPost(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.GetPostRow{}, fmt.Errorf("no post with id %s", id)
+	}
+	if err != nil {
+		return database.GetPostRow{}, fmt.Errorf("get post: %w", err)
+	}
+
+	return post, nil
+}
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name          string
+		alreadyRead   string
+		args          []string
+		expectedPosts []string
+		isErr         bool
+	}{
+		{name: "One Post", args: []string{"{b 2}"}, expectedPosts: []string{"b 1", "b 0", "c 0", "b old"}},
+		{name: "Already Read", alreadyRead: "b 2", args: []string{"{b 2}"}, expectedPosts: []string{"b 1", "b 0", "c 0", "b old"}},
+		{name: "All", args: []string{"--all"}, expectedPosts: nil},
+		{name: "All Of One Feed", args: []string{"--all", "--feed", "Blog C"}, expectedPosts: []string{"b 2", "b 1", "b 0", "b old"}},
+		{name: "Unknown Post", args: []string{uuid.NewString()}, isErr: true},
+		{name: "Invalid ID", args: []string{"not-a-uuid"}, isErr: true},
+		{name: "Feed Without All", args: []string{"{b 2}", "--feed", "Blog B"}, isErr: true},
+		{name: "No Arguments", args: nil, isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, alice := newBrowseState(t)
+
+			if tc.alreadyRead != "" {
+				_, err := captureStdout(t, func() error {
+					return Read(s, Command{Name: "read", Arguments: []string{postID(t, s, alice, tc.alreadyRead)}}, alice)
+				})
+				if err != nil {
+					t.Fatalf("read %s: %v", tc.alreadyRead, err)
+				}
+			}
+
+			_, err := captureStdout(t, func() error {
+				return Read(s, Command{Name: "read", Arguments: withPostIDs(t, s, alice, tc.args)}, alice)
+			})
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %v", tc.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			out, err := captureStdout(t, func() error {
+				return Browse(s, Command{Name: "browse"}, alice)
+			})
+			if err != nil {
+				t.Fatalf("browse: %v", err)
+			}
+
+			titles := browsedTitles(out)
+			if strings.Join(titles, ", ") != strings.Join(tc.expectedPosts, ", ") {
+				t.Errorf("expected unread %v, got %v", tc.expectedPosts, titles)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestUnread(t *testing.T) {
+	s, alice := newBrowseState(t)
+
+	if _, err := captureStdout(t, func() error {
+		return Read(s, Command{Name: "read", Arguments: []string{"--all"}}, alice)
+	}); err != nil {
+		t.Fatalf("read all: %v", err)
+	}
+
+	if _, err := captureStdout(t, func() error {
+		return Unread(s, Command{Name: "unread", Arguments: []string{postID(t, s, alice, "b 1")}}, alice)
+	}); err != nil {
+		t.Fatalf("unread: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return Browse(s, Command{Name: "browse"}, alice)
+	})
+	if err != nil {
+		t.Fatalf("browse: %v", err)
+	}
+	if titles := browsedTitles(out); strings.Join(titles, ", ") != "b 1" {
+		t.Errorf("expected only b 1 unread, got %v", titles)
+	}
+
+	out, err = captureStdout(t, func() error {
+		return Browse(s, Command{Name: "browse", Arguments: []string{"--all"}}, alice)
+	})
+	if err != nil {
+		t.Fatalf("browse --all: %v", err)
+	}
+	if titles := browsedTitles(out); len(titles) != 5 {
+		t.Errorf("expected --all to show all 5 posts, got %v", titles)
+	}
+	if read := strings.Count(out, "(read)"); read != 4 {
+		t.Errorf("expected 4 posts marked read, got %d", read)
+	}
+
+	out, err = captureStdout(t, func() error {
+		return Following(s, Command{Name: "following"}, alice)
+	})
+	if err != nil {
+		t.Fatalf("following: %v", err)
+	}
+	if !strings.Contains(out, "- Blog B (1 unread)") || !strings.Contains(out, "- Blog C (0 unread)") {
+		t.Errorf("expected unread counts per feed, got:\n%s", out)
+	}
+
+	if err := Unread(s, Command{Name: "unread"}, alice); err == nil {
+		t.Errorf("expected error without a post id")
+	}
+	fmt.Printf("✅ Test Passed: unread\n")
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// postID finds a post of newBrowseState by title
+func postID(t *testing.T, s *State, user database.User, title string) string {
+	t.Helper()
+
+	posts, err := s.Db.GetPosts(context.Background(), database.GetPostsParams{UserID: user.ID, PageSize: 100})
+	if err != nil {
+		t.Fatalf("get posts: %v", err)
+	}
+
+	for _, post := range posts {
+		if post.Title == title {
+			return post.ID.String()
+		}
+	}
+
+	t.Fatalf("no post titled %q", title)
+	return ""
+}
+
+
+// withPostIDs swaps "{title}" arguments for that post's id
+func withPostIDs(t *testing.T, s *State, user database.User, args []string) []string {
+	t.Helper()
+
+	var replaced []string
+	for _, arg := range args {
+		if title, ok := strings.CutPrefix(arg, "{"); ok {
+			arg = postID(t, s, user, strings.TrimSuffix(title, "}"))
+		}
+		replaced = append(replaced, arg)
+	}
+
+	return replaced
+}
+
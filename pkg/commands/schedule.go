@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/pkg/rss"
+)
+
+const (
+	// minPollInterval and maxPollInterval bound the learned interval, the
+	// lower bound drops to agg's own interval when that is shorter
+	minPollInterval = 5 * time.Minute
+	maxPollInterval = 24 * time.Hour
+
+	// pollsPerPost is how many times we poll in the typical gap between
+	// two posts, so a daily blog is checked about hourly
+	pollsPerPost = 24
+
+	// cadenceSamples is how many recent posts the cadence is learned from
+	cadenceSamples = 20
+)
+
+
+// feedInterval is the feed's learned polling interval, or agg's interval
+// for feeds that haven't been scheduled yet
+func feedInterval(feed database.Feed, opts scrapeOptions) time.Duration {
+	if feed.PollIntervalSeconds.Valid && feed.PollIntervalSeconds.Int32 > 0 {
+		return time.Duration(feed.PollIntervalSeconds.Int32) * time.Second
+	}
+
+	return opts.interval
+}
+
+
+// learnInterval looks at when the feed's recent posts were published and
+// at the publisher's hints to decide how often to poll it
+func learnInterval(ctx context.Context, s *State, feed database.Feed, hints rss.ScheduleHints, opts scrapeOptions) (time.Duration, error) {
+	params := database.GetRecentPublishDatesParams{
+		FeedID: feed.ID,
+		Limit:  cadenceSamples,
+	}
+
+	history, err := s.Db.GetRecentPublishDates(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("get recent publish dates: %w", err)
+	}
+
+	return pollInterval(history, hints, opts.interval), nil
+}
+
+
+// pollInterval is a fraction of the median gap between posts, never
+// shorter than the publisher's ttl or update period. Feeds with too little
+// history keep the fallback interval.
+func pollInterval(history []time.Time, hints rss.ScheduleHints, fallback time.Duration) time.Duration {
+	interval := fallback
+
+	if gap, ok := medianGap(history); ok {
+		interval = gap / pollsPerPost
+	}
+
+	interval = max(interval, hints.TTL, hints.UpdatePeriod)
+
+	return min(max(interval, min(minPollInterval, fallback)), maxPollInterval)
+}
+
+
+// medianGap needs at least three posts to say anything about a cadence
+func medianGap(history []time.Time) (time.Duration, bool) {
+	if len(history) < 3 {
+		return 0, false
+	}
+
+	sorted := slices.Clone(history)
+	slices.SortFunc(sorted, func(a, b time.Time) int { return b.Compare(a) })
+
+	gaps := make([]time.Duration, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		gaps = append(gaps, sorted[i-1].Sub(sorted[i]))
+	}
+	slices.Sort(gaps)
+
+	return gaps[len(gaps)/2], true
+}
+
+
+// nextFetchDelay is how long to wait before the next poll, pushed past
+// any hour or day the publisher asked us to skip
+func nextFetchDelay(now time.Time, interval time.Duration, hints rss.ScheduleHints) time.Duration {
+	next := now.Add(interval)
+
+	// a week of hours covers every combination of skipHours and skipDays
+	for i := 0; i < 7*24 && hints.Skips(next); i++ {
+		next = next.Truncate(time.Hour).Add(time.Hour)
+	}
+
+	return next.Sub(now)
+}
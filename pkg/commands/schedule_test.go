@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/pkg/rss"
+)
+
+
+func TestPollInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  []time.Time
+		hints    rss.ScheduleHints
+		fallback time.Duration
+		expected time.Duration
+	}{
+		{name: "No Posts", fallback: time.Hour, expected: time.Hour},
+		{name: "One Post", history: postsEvery(time.Hour, 1), fallback: time.Hour, expected: time.Hour},
+		{name: "Two Posts", history: postsEvery(time.Minute, 2), fallback: time.Hour, expected: time.Hour},
+		{name: "Daily Posts", history: postsEvery(24*time.Hour, 10), fallback: 10 * time.Minute, expected: time.Hour},
+		{name: "Five Minute Floor", history: postsEvery(time.Minute, 10), fallback: time.Hour, expected: minPollInterval},
+		{name: "Shorter Agg Interval Lowers The Floor", history: postsEvery(time.Minute, 10), fallback: time.Minute, expected: time.Minute},
+		{name: "Day Ceiling", history: postsEvery(60*24*time.Hour, 10), fallback: time.Hour, expected: maxPollInterval},
+		{name: "TTL Floor", history: postsEvery(24*time.Hour, 10), hints: rss.ScheduleHints{TTL: 3 * time.Hour}, fallback: time.Hour, expected: 3 * time.Hour},
+		{
+			name:     "Update Period Floor",
+			history:  postsEvery(24*time.Hour, 10),
+			hints:    rss.ScheduleHints{UpdatePeriod: 12 * time.Hour},
+			fallback: time.Hour,
+			expected: 12 * time.Hour,
+		},
+		{name: "Hint Past The Ceiling", hints: rss.ScheduleHints{TTL: 48 * time.Hour}, fallback: time.Hour, expected: maxPollInterval},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := pollInterval(tc.history, tc.hints, tc.fallback); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestMedianGap(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		history  []time.Time
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "No Posts"},
+		{name: "Two Posts", history: postsEvery(time.Hour, 2)},
+		{name: "Even Gaps", history: postsEvery(time.Hour, 5), expected: time.Hour, ok: true},
+		{
+			name:     "Unsorted With An Outlier",
+			history:  []time.Time{start.Add(3 * time.Hour), start, start.Add(100 * time.Hour), start.Add(time.Hour)},
+			expected: 2 * time.Hour,
+			ok:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := medianGap(tc.history)
+			if ok != tc.ok || got != tc.expected {
+				t.Errorf("expected %v, %v, got %v, %v", tc.expected, tc.ok, got, ok)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestNextFetchDelay(t *testing.T) {
+	// 2026-10-02 is a Friday
+	friday := time.Date(2026, 10, 2, 21, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		interval time.Duration
+		hints    rss.ScheduleHints
+		expected time.Duration
+	}{
+		{name: "No Hints", now: friday, interval: time.Hour, expected: time.Hour},
+		{name: "Allowed Hour", now: friday, interval: time.Hour, hints: rss.ScheduleHints{SkipHours: []int{12}}, expected: time.Hour},
+		{
+			name:     "Skipped Hour",
+			now:      friday,
+			interval: time.Hour,
+			hints:    rss.ScheduleHints{SkipHours: []int{22}},
+			expected: 90 * time.Minute,
+		},
+		{
+			name:     "Skipped Hours Past Midnight",
+			now:      friday,
+			interval: time.Hour,
+			hints:    rss.ScheduleHints{SkipHours: []int{22, 23, 0}},
+			expected: 3*time.Hour + 30*time.Minute,
+		},
+		{
+			name:     "Skipped Day",
+			now:      friday,
+			interval: 3 * time.Hour,
+			hints:    rss.ScheduleHints{SkipDays: []time.Weekday{time.Saturday}},
+			expected: 26*time.Hour + 30*time.Minute,
+		},
+		{
+			name:     "Skipped Day And Hour",
+			now:      friday,
+			interval: 3 * time.Hour,
+			hints:    rss.ScheduleHints{SkipHours: []int{0}, SkipDays: []time.Weekday{time.Saturday}},
+			expected: 27*time.Hour + 30*time.Minute,
+		},
+		{
+			name:     "Every Hour Skipped",
+			now:      friday,
+			interval: time.Hour,
+			hints:    rss.ScheduleHints{SkipDays: allWeekdays()},
+			expected: 7*24*time.Hour + 30*time.Minute,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := nextFetchDelay(tc.now, tc.interval, tc.hints); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestFeedInterval(t *testing.T) {
+	opts := scrapeOptions{interval: time.Hour}
+
+	tests := []struct {
+		name     string
+		learned  sql.NullInt32
+		expected time.Duration
+	}{
+		{name: "Never Scheduled", expected: time.Hour},
+		{name: "Learned", learned: sql.NullInt32{Int32: 600, Valid: true}, expected: 10 * time.Minute},
+		{name: "Zero Interval", learned: sql.NullInt32{Int32: 0, Valid: true}, expected: time.Hour},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			feed := database.Feed{PollIntervalSeconds: tc.learned}
+			if got := feedInterval(feed, opts); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// postsEvery returns count publish dates gap apart, newest first
+func postsEvery(gap time.Duration, count int) []time.Time {
+	newest := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	history := make([]time.Time, count)
+	for i := range history {
+		history[i] = newest.Add(-time.Duration(i) * gap)
+	}
+
+	return history
+}
+
+
+func allWeekdays() []time.Weekday {
+	days := make([]time.Weekday, 7)
+	for i := range days {
+		days[i] = time.Weekday(i)
+	}
+
+	return days
+}
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/OriElbaz/gatorcli/internal/search"
+	"github.com/OriElbaz/gatorcli/internal/store"
+	"github.com/google/uuid"
+)
+
+const defaultSearchLimit = 10
+
+
+/****** COMMANDS ******/
+
+// Search finds the posts of the user's followed feeds matching a query,
+// best match first, with the matched words highlighted
+func Search(s *State, cmd Command, user database.User) error {
+	query, feedFilter, limit, err := parseSearchArgs(cmd.Arguments)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := search.Parse(query)
+	if err != nil {
+		return fmt.Errorf("usage: search <query> [--feed url|name] [--limit N]: %w", err)
+	}
+
+	ctx := context.Background()
+
+	params := store.SearchParams{
+		UserID: user.ID,
+		Query:  parsed,
+		Limit:  int32(limit),
+	}
+
+	if feedFilter != "" {
+		feed, err := findFeed(ctx, s, feedFilter)
+		if err != nil {
+			return err
+		}
+		params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+
+	results, err := s.Db.Search(ctx, params)
+	if err != nil {
+		return fmt.Errorf("search: %w", err)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No posts match")
+		return nil
+	}
+
+	for _, result := range results {
+		fmt.Printf("*** %s: %s\n", result.TitleHighlight, result.Url)
+		fmt.Printf("ID: %s\n", result.ID)
+		fmt.Printf("Feed: %s, published %s\n", result.FeedName, result.PublishedAt.Local().Format(time.DateTime))
+		if result.Snippet != "" {
+			fmt.Printf("%s\n", result.Snippet)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// parseSearchArgs picks out --feed and --limit by hand, the flag package
+// would take an excluded -word for an unknown flag. Everything else is the
+// query
+func parseSearchArgs(args []string) (query string, feedFilter string, limit int, err error) {
+	limit = defaultSearchLimit
+
+	var terms []string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+
+		switch name {
+		case "--feed", "-feed", "--limit", "-limit":
+			if !hasValue {
+				if i+1 == len(args) {
+					return "", "", 0, fmt.Errorf("%s needs a value", name)
+				}
+				i++
+				value = args[i]
+			}
+
+			if strings.HasSuffix(name, "feed") {
+				feedFilter = value
+				continue
+			}
+
+			limit, err = strconv.Atoi(value)
+			if err != nil || limit < 1 {
+				return "", "", 0, fmt.Errorf("limit must be a positive number, got %q", value)
+			}
+			if limit > math.MaxInt32 {
+				return "", "", 0, fmt.Errorf("limit must be at most %d, got %d", math.MaxInt32, limit)
+			}
+		default:
+			terms = append(terms, args[i])
+		}
+	}
+
+	return strings.Join(terms, " "), feedFilter, limit, nil
+}
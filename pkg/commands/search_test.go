@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedPosts []string
+		isErr         bool
+	}{
+		{name: "Title Ranks First", args: []string{"generics"}, expectedPosts: []string{"Go generics", "Release notes"}},
+		{name: "Every Word Must Match", args: []string{"generics", "faster"}, expectedPosts: []string{"Release notes"}},
+		{name: "Phrase", args: []string{`"garbage collector"`}, expectedPosts: []string{"Release notes"}},
+		{name: "Prefix", args: []string{"gen*"}, expectedPosts: []string{"Go generics", "Release notes"}},
+		{name: "Or", args: []string{"rust", "OR", "collector"}, expectedPosts: []string{"Rust 2.0", "Release notes"}},
+		{name: "Excluded Word", args: []string{"generics", "-faster"}, expectedPosts: []string{"Go generics"}},
+		{name: "One Feed", args: []string{"rust", "OR", "generics", "--feed", "Blog C"}, expectedPosts: []string{"Rust 2.0"}},
+		{name: "Limit", args: []string{"--limit=1", "generics"}, expectedPosts: []string{"Go generics"}},
+		{name: "Only Followed Feeds", args: []string{"unfollowed"}, expectedPosts: nil},
+		{name: "No Words", args: []string{"-generics"}, isErr: true},
+		{name: "Missing Limit Value", args: []string{"generics", "--limit"}, isErr: true},
+		{name: "Limit Too Large", args: []string{"generics", "--limit", "4294967297"}, isErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, alice := newSearchState(t)
+
+			out, err := captureStdout(t, func() error {
+				return Search(s, Command{Name: "search", Arguments: tc.args}, alice)
+			})
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %v", tc.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var titles []string
+			for _, title := range browsedTitles(out) {
+				titles = append(titles, strings.ReplaceAll(title, "**", ""))
+			}
+			if strings.Join(titles, ", ") != strings.Join(tc.expectedPosts, ", ") {
+				t.Errorf("expected %v, got %v", tc.expectedPosts, titles)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestSearchHighlights(t *testing.T) {
+	s, alice := newSearchState(t)
+
+	out, err := captureStdout(t, func() error {
+		return Search(s, Command{Name: "search", Arguments: []string{"generics"}}, alice)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"*** Go **generics**: ", "Type parameters and **generics** in depth", "Feed: Blog B"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<p>") {
+		t.Errorf("expected html tags stripped from snippets, got:\n%s", out)
+	}
+	fmt.Printf("✅ Test Passed: search highlights\n")
+}
+
+
+/** HELPER FUNCTIONS **/
+
+// newSearchState has alice follow two of bob's feeds, with a third feed
+// she doesn't follow
+func newSearchState(t *testing.T) (*State, database.User) {
+	t.Helper()
+
+	s, alice := newTestState(t)
+	bob := addTestUser(t, s, "bob")
+
+	feedA := addTestFeed(t, s, bob, "Blog A", "https://example.com/a.xml")
+	feedB := addTestFeed(t, s, bob, "Blog B", "https://example.com/b.xml")
+	feedC := addTestFeed(t, s, bob, "Blog C", "https://example.com/c.xml")
+
+	for _, feed := range []database.Feed{feedB, feedC} {
+		if _, err := createFeedFollowHelper(s, alice.ID, feed.ID); err != nil {
+			t.Fatalf("follow feed: %v", err)
+		}
+	}
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	addDescribedPost(t, s, feedA, "Unfollowed generics", "", day)
+	addDescribedPost(t, s, feedB, "Go generics", "<p>Type parameters and generics in depth.</p>", day.Add(time.Hour))
+	addDescribedPost(t, s, feedB, "Release notes", "<p>Better generics and a faster garbage collector.</p>", day.Add(2*time.Hour))
+	addDescribedPost(t, s, feedC, "Rust 2.0", "A new edition.", day.Add(3*time.Hour))
+
+	return s, alice
+}
+
+
+func addDescribedPost(t *testing.T, s *State, feed database.Feed, title string, description string, publishedAt time.Time) {
+	t.Helper()
+
+	_, err := s.Db.CreatePost(context.Background(), database.CreatePostParams{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		Title:       title,
+		Url:         feed.Url.String + "#" + title,
+		Description: sql.NullString{String: description, Valid: description != ""},
+		PublishedAt: publishedAt,
+		FeedID:      feed.ID,
+	})
+	if err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OriElbaz/gatorcli/internal/database"
+)
+
+
+/****** COMMANDS ******/
+
+// Star saves a post for later, it stays listed by starred until unstarred
+func Star(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: star <post-id>")
+	}
+
+	ctx := context.Background()
+
+	post, err := findPost(ctx, s, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+
+	params := database.StarPostParams{
+		UserID:    user.ID,
+		PostID:    post.ID,
+		StarredAt: time.Now(),
+	}
+
+	if err := s.Db.StarPost(ctx, params); err != nil {
+		return fmt.Errorf("star post: %w", err)
+	}
+
+	fmt.Printf("Starred %q\n", post.Title)
+	return nil
+}
+
+
+func Unstar(s *State, cmd Command, user database.User) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: unstar <post-id>")
+	}
+
+	ctx := context.Background()
+
+	post, err := findPost(ctx, s, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+
+	params := database.UnstarPostParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	}
+
+	unstarred, err := s.Db.UnstarPost(ctx, params)
+	if err != nil {
+		return fmt.Errorf("unstar post: %w", err)
+	}
+
+	if unstarred == 0 {
+		return fmt.Errorf("%q is not starred", post.Title)
+	}
+
+	fmt.Printf("Unstarred %q\n", post.Title)
+	return nil
+}
+
+
+// Starred lists the user's starred posts, most recently starred first
+func Starred(s *State, cmd Command, user database.User) error {
+	posts, err := s.Db.GetStarredPosts(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("get starred posts: %w", err)
+	}
+
+	if len(posts) == 0 {
+		fmt.Println("No starred posts")
+		return nil
+	}
+
+	for _, post := range posts {
+		fmt.Printf("*** %s: %s\n", post.Title, post.Url)
+		fmt.Printf("ID: %s\n", post.ID)
+		fmt.Printf("Feed: %s, starred %s\n", post.FeedName, post.StarredAt.Local().Format(time.DateTime))
+		fmt.Print("Description: \n")
+		fmt.Printf("%s\n\n", post.Description.String)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+
+func TestStar(t *testing.T) {
+	tests := []struct {
+		name          string
+		star          []string
+		unstar        []string
+		expectedPosts []string
+		isErr         bool
+	}{
+		{name: "Newest Star First", star: []string{"b old", "c 0"}, expectedPosts: []string{"c 0", "b old"}},
+		{name: "Star Twice", star: []string{"b 1", "b 1"}, expectedPosts: []string{"b 1"}},
+		{name: "Unstar", star: []string{"b 1", "b 2"}, unstar: []string{"b 1"}, expectedPosts: []string{"b 2"}},
+		{name: "Unstar Unstarred Post", star: []string{"b 1"}, unstar: []string{"b 2"}, isErr: true},
+		{name: "Nothing Starred", expectedPosts: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, alice := newBrowseState(t)
+
+			var err error
+			for _, title := range tc.star {
+				_, err = captureStdout(t, func() error {
+					return Star(s, Command{Name: "star", Arguments: []string{postID(t, s, alice, title)}}, alice)
+				})
+				if err != nil {
+					t.Fatalf("star %s: %v", title, err)
+				}
+			}
+
+			for _, title := range tc.unstar {
+				_, err = captureStdout(t, func() error {
+					return Unstar(s, Command{Name: "unstar", Arguments: []string{postID(t, s, alice, title)}}, alice)
+				})
+			}
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error unstarring %v", tc.unstar)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			out, err := captureStdout(t, func() error {
+				return Starred(s, Command{Name: "starred"}, alice)
+			})
+			if err != nil {
+				t.Fatalf("starred: %v", err)
+			}
+
+			titles := browsedTitles(out)
+			if strings.Join(titles, ", ") != strings.Join(tc.expectedPosts, ", ") {
+				t.Errorf("expected starred %v, got %v", tc.expectedPosts, titles)
+			}
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestStarArguments(t *testing.T) {
+	s, alice := newBrowseState(t)
+
+	for _, args := range [][]string{nil, {"not-a-uuid"}, {uuid.NewString()}} {
+		if err := Star(s, Command{Name: "star", Arguments: args}, alice); err == nil {
+			t.Errorf("expected star %v to fail", args)
+		}
+		if err := Unstar(s, Command{Name: "unstar", Arguments: args}, alice); err == nil {
+			t.Errorf("expected unstar %v to fail", args)
+		}
+	}
+	fmt.Printf("✅ Test Passed: star arguments\n")
+}
@@ -0,0 +1,95 @@
+package rss
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+
+type atomFeed struct {
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+
+type atomEntry struct {
+	Title     string     `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	Summary   atomText   `xml:"summary"`
+	Content   atomText   `xml:"content"`
+	Updated   string     `xml:"updated"`
+	Published string     `xml:"published"`
+}
+
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+
+// atomText is a summary or content element, whose type="xhtml" body is
+// markup rather than text
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+
+func (t atomText) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+
+	return t.Text
+}
+
+
+func parseAtom(data []byte) (*RSSFeed, error) {
+	var atom atomFeed
+	if err := xml.Unmarshal(data, &atom); err != nil {
+		return &RSSFeed{}, err
+	}
+
+	feed := &RSSFeed{}
+	feed.Channel.Title = atom.Title
+	feed.Channel.Link = alternateLink(atom.Links)
+	feed.Channel.Description = atom.Subtitle
+
+	for _, entry := range atom.Entries {
+		description := firstNonEmpty(entry.Summary.String(), entry.Content.String())
+
+		pubDate := entry.Published
+		if pubDate == "" {
+			pubDate = entry.Updated
+		}
+
+		feed.Channel.Item = append(feed.Channel.Item, RSSItem{
+			Title:       entry.Title,
+			Link:        alternateLink(entry.Links),
+			Description: description,
+			PubDate:     pubDate,
+		})
+	}
+
+	return feed, nil
+}
+
+
+// alternateLink picks the rel="alternate" link, which is also the
+// default when rel is omitted
+func alternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+
+	return ""
+}
+
@@ -0,0 +1,223 @@
+package rss
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrNoDate = errors.New("no date")
+
+
+// dateLayouts are tried in order after normalizeDate has removed the
+// weekday and replaced any timezone abbreviation with a numeric offset
+var dateLayouts = []string{
+	// RFC 822 / 1123 and their common variants
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 -07:00",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 January 2006 15:04:05 -0700",
+	"2 January 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 January 2006 15:04:05",
+	"2 Jan 2006",
+	"2 January 2006",
+	"2-Jan-06 15:04:05 -0700",
+	"2-Jan-2006 15:04:05 -0700",
+
+	// ANSI C and Unix date
+	"Jan 2 15:04:05 2006",
+	"Jan 2 15:04:05 -0700 2006",
+	"January 2, 2006 15:04:05 -0700",
+	"January 2, 2006",
+	"Jan 2, 2006",
+
+	// RFC 3339 / W3C-DTF
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+
+// zoneOffsets maps timezone abbreviations to their UTC offset in minutes.
+// time.Parse invents a zero-offset zone for abbreviations it doesn't know,
+// so they are replaced before parsing.
+var zoneOffsets = map[string]int{
+	"UT": 0, "UTC": 0, "GMT": 0, "Z": 0, "WET": 0,
+	"WEST": 60, "BST": 60, "CET": 60, "MET": 60,
+	"CEST": 120, "MEST": 120, "EET": 120, "SAST": 120,
+	"EEST": 180, "MSK": 180, "IDT": 180,
+	"IST": 330,
+	"SGT": 480, "HKT": 480, "AWST": 480,
+	"JST": 540, "KST": 540,
+	"ACST": 570, "ACDT": 630,
+	"AEST": 600, "AEDT": 660,
+	"NZST": 720, "NZDT": 780,
+	"NST": -210, "NDT": -150,
+	"AST": -240, "ADT": -180,
+	"EST": -300, "EDT": -240,
+	"CST": -360, "CDT": -300,
+	"MST": -420, "MDT": -360,
+	"PST": -480, "PDT": -420,
+	"AKST": -540, "AKDT": -480,
+	"HST": -600,
+}
+
+
+var weekdays = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+
+// ParseDate parses a feed publish date in any of the formats seen in the
+// wild: RFC 1123/1123Z/822/3339, W3C-DTF and their common malformations
+func ParseDate(value string) (time.Time, error) {
+	normalized := normalizeDate(value)
+	if normalized == "" {
+		return time.Time{}, ErrNoDate
+	}
+
+	for _, layout := range dateLayouts {
+		t, err := time.Parse(layout, normalized)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognised date format %q", value)
+}
+
+
+func normalizeDate(value string) string {
+	// drop trailing comments such as "+0000 (UTC)"
+	if i := strings.Index(value, "("); i > 0 {
+		value = value[:i]
+	}
+
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	if isWeekday(fields[0]) {
+		fields = fields[1:]
+	}
+
+	for i, field := range fields {
+		if strings.EqualFold(field, "Sept") {
+			field = "Sep"
+		}
+		fields[i] = normalizeZone(field)
+	}
+
+	return strings.Join(fields, " ")
+}
+
+
+func isWeekday(field string) bool {
+	field = strings.ToLower(strings.TrimSuffix(field, ","))
+	if len(field) < 3 {
+		return false
+	}
+
+	for _, char := range field {
+		if char < 'a' || char > 'z' {
+			return false
+		}
+	}
+
+	for _, day := range weekdays {
+		if strings.HasPrefix(field, day) {
+			return true
+		}
+	}
+
+	return false
+}
+
+
+// normalizeZone turns "EST", "GMT+2" or "UTC+05:30" into a numeric offset
+// and leaves any other field untouched
+func normalizeZone(field string) string {
+	upper := strings.ToUpper(field)
+
+	if offset, ok := zoneOffsets[upper]; ok {
+		return formatOffset(offset)
+	}
+
+	for _, prefix := range []string{"GMT", "UTC"} {
+		rest, ok := strings.CutPrefix(upper, prefix)
+		if !ok || rest == "" || (rest[0] != '+' && rest[0] != '-') {
+			continue
+		}
+
+		offset, err := parseOffset(rest)
+		if err == nil {
+			return formatOffset(offset)
+		}
+	}
+
+	return field
+}
+
+
+// parseOffset reads "+2", "+0530" or "-05:30" as minutes east of UTC
+func parseOffset(value string) (int, error) {
+	sign := 1
+	if value[0] == '-' {
+		sign = -1
+	}
+
+	digits := strings.ReplaceAll(value[1:], ":", "")
+
+	var hours, minutes int
+	switch len(digits) {
+	case 1, 2:
+		if _, err := fmt.Sscanf(digits, "%d", &hours); err != nil {
+			return 0, err
+		}
+	case 4:
+		if _, err := fmt.Sscanf(digits, "%2d%2d", &hours, &minutes); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+
+	return sign * (hours*60 + minutes), nil
+}
+
+
+func formatOffset(minutes int) string {
+	sign := '+'
+	if minutes < 0 {
+		sign = '-'
+		minutes = -minutes
+	}
+
+	return fmt.Sprintf("%c%02d%02d", sign, minutes/60, minutes%60)
+}
+
+
+// resolvePublishDates parses every item's PubDate, falling back to the
+// fetch time for items with a missing or unparseable date
+func resolvePublishDates(feed *RSSFeed) {
+	for i := range feed.Channel.Item {
+		item := &feed.Channel.Item[i]
+
+		publishedAt, err := ParseDate(item.PubDate)
+		if err != nil {
+			item.PublishedAt = feed.FetchedAt
+			item.DateFallback = true
+			continue
+		}
+
+		item.PublishedAt = publishedAt
+	}
+}
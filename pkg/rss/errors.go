@@ -0,0 +1,192 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+
+// NotFoundError is returned for a 404, the feed may come back later
+type NotFoundError struct {
+	URL string
+}
+
+
+// GoneError is returned for a 410, the publisher removed the feed for good
+type GoneError struct {
+	URL string
+}
+
+
+// RateLimitedError is returned for a 429, RetryAfter is zero when the
+// server didn't say how long to wait
+type RateLimitedError struct {
+	URL        string
+	RetryAfter time.Duration
+}
+
+
+// ServerError is returned for any 5xx, a 503 may carry a RetryAfter
+type ServerError struct {
+	URL        string
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+
+// HTTPError is returned for any other unexpected status
+type HTTPError struct {
+	URL        string
+	StatusCode int
+}
+
+
+// ParseError wraps a body that couldn't be decoded as any feed format
+type ParseError struct {
+	URL string
+	Err error
+}
+
+
+// TimeoutError wraps a request that ran out of time
+type TimeoutError struct {
+	URL string
+	Err error
+}
+
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s: not found (404)", e.URL)
+}
+
+
+func (e *GoneError) Error() string {
+	return fmt.Sprintf("%s: gone (410)", e.URL)
+}
+
+
+func (e *RateLimitedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s: rate limited (429), retry after %s", e.URL, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s: rate limited (429)", e.URL)
+}
+
+
+func (e *ServerError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s: server error (%d), retry after %s", e.URL, e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s: server error (%d)", e.URL, e.StatusCode)
+}
+
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s: unexpected status %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: parse feed: %v", e.URL, e.Err)
+}
+
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s: timed out: %v", e.URL, e.Err)
+}
+
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+
+// statusError maps a non-2xx response to one of the typed errors above
+func statusError(feedURL string, res *http.Response) error {
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return &NotFoundError{URL: feedURL}
+	case res.StatusCode == http.StatusGone:
+		return &GoneError{URL: feedURL}
+	case res.StatusCode == http.StatusTooManyRequests:
+		return &RateLimitedError{URL: feedURL, RetryAfter: retryAfter(res.Header.Get("Retry-After"))}
+	case res.StatusCode >= 500:
+		return &ServerError{URL: feedURL, StatusCode: res.StatusCode, RetryAfter: retryAfter(res.Header.Get("Retry-After"))}
+	default:
+		return &HTTPError{URL: feedURL, StatusCode: res.StatusCode}
+	}
+}
+
+
+// retryAfter reads a Retry-After header given either in seconds or as an
+// HTTP date
+func retryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
+
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+
+// StatusCode returns the HTTP status carried by a fetch error, or 0 when
+// the request never got a response
+func StatusCode(err error) int {
+	var (
+		notFound    *NotFoundError
+		gone        *GoneError
+		rateLimited *RateLimitedError
+		serverErr   *ServerError
+		httpErr     *HTTPError
+	)
+
+	switch {
+	case errors.As(err, &notFound):
+		return http.StatusNotFound
+	case errors.As(err, &gone):
+		return http.StatusGone
+	case errors.As(err, &rateLimited):
+		return http.StatusTooManyRequests
+	case errors.As(err, &serverErr):
+		return serverErr.StatusCode
+	case errors.As(err, &httpErr):
+		return httpErr.StatusCode
+	default:
+		return 0
+	}
+}
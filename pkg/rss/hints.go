@@ -0,0 +1,110 @@
+package rss
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+
+// ScheduleHints are the publisher's own hints on how often the feed
+// changes, taken from <ttl>, sy:updatePeriod/sy:updateFrequency,
+// <skipHours> and <skipDays>. Zero values mean no hint was given.
+type ScheduleHints struct {
+	TTL          time.Duration
+	UpdatePeriod time.Duration
+	SkipHours    []int // hours in GMT, 0-23
+	SkipDays     []time.Weekday
+}
+
+
+type channelHints struct {
+	Channel struct {
+		TTL             string   `xml:"ttl"`
+		UpdatePeriod    string   `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+		UpdateFrequency string   `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+		SkipHours       []string `xml:"skipHours>hour"`
+		SkipDays        []string `xml:"skipDays>day"`
+	} `xml:"channel"`
+}
+
+
+var updatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+
+// parseHints reads the scheduling hints of an RSS 2.0 or RSS 1.0 channel,
+// ignoring any value it doesn't understand
+func parseHints(data []byte) ScheduleHints {
+	var raw channelHints
+	if err := xml.Unmarshal(data, &raw); err != nil {
+		return ScheduleHints{}
+	}
+
+	hints := ScheduleHints{}
+
+	if minutes, err := strconv.Atoi(strings.TrimSpace(raw.Channel.TTL)); err == nil && minutes > 0 {
+		hints.TTL = time.Duration(minutes) * time.Minute
+	}
+
+	if period, ok := updatePeriods[strings.ToLower(strings.TrimSpace(raw.Channel.UpdatePeriod))]; ok {
+		frequency, err := strconv.Atoi(strings.TrimSpace(raw.Channel.UpdateFrequency))
+		if err != nil || frequency < 1 {
+			frequency = 1
+		}
+		hints.UpdatePeriod = period / time.Duration(frequency)
+	}
+
+	for _, value := range raw.Channel.SkipHours {
+		hour, err := strconv.Atoi(strings.TrimSpace(value))
+		if err == nil && hour >= 0 && hour <= 24 {
+			// some publishers count hours 1-24
+			hints.SkipHours = append(hints.SkipHours, hour%24)
+		}
+	}
+
+	for _, value := range raw.Channel.SkipDays {
+		if day, ok := weekdayNames[strings.ToLower(strings.TrimSpace(value))]; ok {
+			hints.SkipDays = append(hints.SkipDays, day)
+		}
+	}
+
+	return hints
+}
+
+
+// Skips reports whether the publisher asked not to be polled at t
+func (h ScheduleHints) Skips(t time.Time) bool {
+	t = t.UTC()
+
+	for _, hour := range h.SkipHours {
+		if t.Hour() == hour {
+			return true
+		}
+	}
+
+	for _, day := range h.SkipDays {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+
+	return false
+}
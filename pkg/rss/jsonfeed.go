@@ -0,0 +1,103 @@
+package rss
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	Description string         `json:"description"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	ExternalURL   string           `json:"external_url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors"`
+	Author        *jsonFeedAuthor  `json:"author"` // JSON Feed 1.0
+}
+
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+
+func parseJSONFeed(data []byte) (*RSSFeed, error) {
+	var jf jsonFeed
+	if err := json.Unmarshal(data, &jf); err != nil {
+		return &RSSFeed{}, err
+	}
+
+	feed := &RSSFeed{}
+	feed.Channel.Title = jf.Title
+	feed.Channel.Link = jf.HomePageURL
+	feed.Channel.Description = jf.Description
+
+	for _, item := range jf.Items {
+		link := item.URL
+		if link == "" {
+			link = item.ExternalURL
+		}
+
+		description := firstNonEmpty(item.ContentHTML, item.ContentText, item.Summary)
+
+		pubDate := item.DatePublished
+		if pubDate == "" {
+			pubDate = item.DateModified
+		}
+
+		authors := item.Authors
+		if len(authors) == 0 && item.Author != nil {
+			authors = []jsonFeedAuthor{*item.Author}
+		}
+
+		var names []string
+		for _, author := range authors {
+			if author.Name != "" {
+				names = append(names, author.Name)
+			}
+		}
+
+		feed.Channel.Item = append(feed.Channel.Item, RSSItem{
+			Title:       item.Title,
+			Link:        link,
+			Description: description,
+			PubDate:     pubDate,
+			Author:      strings.Join(names, ", "),
+		})
+	}
+
+	return feed, nil
+}
+
+
+// isJSON reports whether the document looks like JSON rather than XML
+func isJSON(data []byte) bool {
+	trimmed := strings.TrimLeft(string(data), " \t\r\n\ufeff")
+	return strings.HasPrefix(trimmed, "{")
+}
+
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+
+	return ""
+}
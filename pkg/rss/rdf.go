@@ -0,0 +1,53 @@
+package rss
+
+import (
+	"encoding/xml"
+)
+
+const rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+
+// rdfFeed is an RSS 1.0 document, where items are siblings of the
+// channel rather than children of it
+type rdfFeed struct {
+	Channel struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+	} `xml:"channel"`
+	Items []rdfItem `xml:"item"`
+}
+
+
+type rdfItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+}
+
+
+func parseRDF(data []byte) (*RSSFeed, error) {
+	var rdf rdfFeed
+	if err := xml.Unmarshal(data, &rdf); err != nil {
+		return &RSSFeed{}, err
+	}
+
+	feed := &RSSFeed{}
+	feed.Channel.Title = rdf.Channel.Title
+	feed.Channel.Link = rdf.Channel.Link
+	feed.Channel.Description = rdf.Channel.Description
+
+	for _, item := range rdf.Items {
+		feed.Channel.Item = append(feed.Channel.Item, RSSItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			PubDate:     item.Date,
+			Author:      item.Creator,
+		})
+	}
+
+	return feed, nil
+}
@@ -1,16 +1,21 @@
 package rss
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
 	"fmt"
 	"html"
 	"io"
 	"net/http"
+	"time"
 	"github.com/microcosm-cc/bluemonday"
 )
 
 
+const fetchTimeout = 30 * time.Second
+
+
 type RSSFeed struct {
 	Channel struct {
 		Title       string    `xml:"title"`
@@ -18,6 +23,24 @@ type RSSFeed struct {
 		Description string    `xml:"description"`
 		Item        []RSSItem `xml:"item"`
 	} `xml:"channel"`
+	FetchedAt time.Time `xml:"-"`
+
+	// Cache holds the validators to send on the next fetch, NotModified is
+	// set when the server answered 304 and the feed has no items
+	Cache       CacheValidators `xml:"-"`
+	NotModified bool            `xml:"-"`
+
+	// StatusCode and Bytes describe the response the feed was read from
+	StatusCode int `xml:"-"`
+	Bytes      int `xml:"-"`
+
+	Hints ScheduleHints `xml:"-"`
+}
+
+
+type CacheValidators struct {
+	ETag         string
+	LastModified string
 }
 
 
@@ -26,40 +49,163 @@ type RSSItem struct {
 	Link        string `xml:"link"`
 	Description string `xml:"description"`
 	PubDate     string `xml:"pubDate"`
+	Author      string `xml:"author"`
+
+	// set by FetchFeed from PubDate, DateFallback marks items whose date
+	// was missing or unparseable and got the fetch time instead
+	PublishedAt  time.Time `xml:"-"`
+	DateFallback bool      `xml:"-"`
 }
 
 
 func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
+	return FetchFeedIfModified(ctx, feedURL, CacheValidators{})
+}
+
+
+// FetchFeedIfModified sends the cache validators from a previous fetch as
+// If-None-Match / If-Modified-Since so unchanged feeds cost a 304
+func FetchFeedIfModified(ctx context.Context, feedURL string, cache CacheValidators) (*RSSFeed, error) {
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
 	if err != nil {
 		return &RSSFeed{}, fmt.Errorf("get: %w", err)
 	}
 
+	if cache.ETag != "" {
+		req.Header.Set("If-None-Match", cache.ETag)
+	}
+	if cache.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cache.LastModified)
+	}
+
 	req.Header.Set("User-Agent", "gatorcli")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, */*;q=0.8")
 
-	client := &http.Client{}
+	client := &http.Client{Timeout: fetchTimeout}
 	res, err := client.Do(req)
 	if err != nil {
+		if isTimeout(err) {
+			return &RSSFeed{}, &TimeoutError{URL: feedURL, Err: err}
+		}
 		return &RSSFeed{}, fmt.Errorf("http client do: %w", err)
 	}
 	defer res.Body.Close()
 
+	validators := CacheValidators{
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+	}
+
+	if res.StatusCode == http.StatusNotModified {
+		// a 304 may omit the validators, in which case the old ones still hold
+		if validators.ETag == "" {
+			validators.ETag = cache.ETag
+		}
+		if validators.LastModified == "" {
+			validators.LastModified = cache.LastModified
+		}
+
+		return &RSSFeed{
+			FetchedAt:   time.Now(),
+			Cache:       validators,
+			NotModified: true,
+			StatusCode:  res.StatusCode,
+		}, nil
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &RSSFeed{}, statusError(feedURL, res)
+	}
+
 	byteData, err := io.ReadAll(res.Body)
 	if err != nil {
+		if isTimeout(err) {
+			return &RSSFeed{}, &TimeoutError{URL: feedURL, Err: err}
+		}
 		return &RSSFeed{}, fmt.Errorf("read all: %w", err)
 	}
 
+	htmx, err := parseFeed(byteData)
+	if err != nil {
+		return &RSSFeed{}, &ParseError{URL: feedURL, Err: err}
+	}
+
+	htmx.FetchedAt = time.Now()
+	htmx.Cache = validators
+	htmx.StatusCode = res.StatusCode
+	htmx.Bytes = len(byteData)
+
+	cleanText(htmx)
+	resolvePublishDates(htmx)
+
+	return htmx, nil
+}
+
+
+// parseFeed looks at the root element to decide which format to decode
+func parseFeed(data []byte) (*RSSFeed, error) {
+	if isJSON(data) {
+		feed, err := parseJSONFeed(data)
+		if err != nil {
+			return &RSSFeed{}, fmt.Errorf("unmarshal json feed: %w", err)
+		}
+		return feed, nil
+	}
+
+	root, err := rootElement(data)
+	if err != nil {
+		return &RSSFeed{}, fmt.Errorf("find root element: %w", err)
+	}
+
+	if root.Space == rdfNamespace && root.Local == "RDF" {
+		feed, err := parseRDF(data)
+		if err != nil {
+			return &RSSFeed{}, fmt.Errorf("unmarshal rdf: %w", err)
+		}
+		feed.Hints = parseHints(data)
+		return feed, nil
+	}
+
+	if root.Space == atomNamespace && root.Local == "feed" {
+		feed, err := parseAtom(data)
+		if err != nil {
+			return &RSSFeed{}, fmt.Errorf("unmarshal atom: %w", err)
+		}
+		return feed, nil
+	}
+
+	// anything else well formed, such as an html page served with a 200,
+	// would otherwise decode as an empty feed
+	if root.Local != "rss" {
+		return &RSSFeed{}, fmt.Errorf("unsupported root element <%s>", root.Local)
+	}
+
 	var htmx RSSFeed
-	if err := xml.Unmarshal(byteData, &htmx); err != nil {
+	if err := xml.Unmarshal(data, &htmx); err != nil {
 		return &RSSFeed{}, fmt.Errorf("unmarshal htmx: %w", err)
 	}
-
-	cleanText(&htmx)
+	htmx.Hints = parseHints(data)
 
 	return &htmx, nil
 }
 
+
+func rootElement(data []byte) (xml.Name, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return xml.Name{}, err
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name, nil
+		}
+	}
+}
+
 func cleanText (feed *RSSFeed) error {
 	p := bluemonday.StrictPolicy()
 
@@ -72,4 +218,5 @@ func cleanText (feed *RSSFeed) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
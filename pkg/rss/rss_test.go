@@ -3,10 +3,12 @@ package rss
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestFetchFeed(t *testing.T) {
@@ -33,6 +35,48 @@ func TestFetchFeed(t *testing.T) {
 			expectedTitle: "Go Blog",
 			expectedItems: 2,
 		},
+		{
+			name:       "Valid Atom Feed",
+			mockStatus: http.StatusOK,
+			mockBody: `<?xml version="1.0" encoding="UTF-8"?>
+				<feed xmlns="http://www.w3.org/2005/Atom">
+					<title>Release notes</title>
+					<entry><title>v1.0.0</title></entry>
+					<entry><title>v1.1.0</title></entry>
+					<entry><title>v1.2.0</title></entry>
+				</feed>`,
+			expectErr:     false,
+			expectedTitle: "Release notes",
+			expectedItems: 3,
+		},
+		{
+			name:       "Valid JSON Feed",
+			mockStatus: http.StatusOK,
+			mockBody: `{
+				"version": "https://jsonfeed.org/version/1.1",
+				"title": "JSON Blog",
+				"items": [
+					{"id": "1", "content_text": "First"},
+					{"id": "2", "content_text": "Second"}
+				]
+			}`,
+			expectErr:     false,
+			expectedTitle: "JSON Blog",
+			expectedItems: 2,
+		},
+		{
+			name:       "Valid RDF Feed",
+			mockStatus: http.StatusOK,
+			mockBody: `<?xml version="1.0" encoding="UTF-8"?>
+				<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+					<channel><title>Journal</title></channel>
+					<item><title>Paper 1</title></item>
+					<item><title>Paper 2</title></item>
+				</rdf:RDF>`,
+			expectErr:     false,
+			expectedTitle: "Journal",
+			expectedItems: 2,
+		},
 		{
 			name:       "Malformed XML",
 			mockStatus: http.StatusOK,
@@ -193,4 +237,504 @@ func TestCleanText(t *testing.T) {
             fmt.Printf("✅ Test Passed: %s\n", tc.name)
         })
     }
-}
\ No newline at end of file
+}
+
+func TestParseAtom(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    string
+		expected RSSItem
+	}
+
+	tests := []testCase{
+		{
+			name: "Alternate link, summary and published date",
+			input: `<feed xmlns="http://www.w3.org/2005/Atom"><entry>
+				<title>Hello</title>
+				<link rel="self" href="https://example.com/self"/>
+				<link rel="alternate" href="https://example.com/hello"/>
+				<summary>Short summary</summary>
+				<content type="html">Full content</content>
+				<updated>2024-03-05T10:00:00Z</updated>
+				<published>2024-03-04T08:30:00+02:00</published>
+			</entry></feed>`,
+			expected: RSSItem{
+				Title:       "Hello",
+				Link:        "https://example.com/hello",
+				Description: "Short summary",
+				PubDate:     "2024-03-04T08:30:00+02:00",
+			},
+		},
+		{
+			name: "Link without rel, content fallback and updated date",
+			input: `<feed xmlns="http://www.w3.org/2005/Atom"><entry>
+				<title>No summary</title>
+				<link href="https://example.com/no-summary"/>
+				<content>Only content</content>
+				<updated>2024-03-05T10:00:00Z</updated>
+			</entry></feed>`,
+			expected: RSSItem{
+				Title:       "No summary",
+				Link:        "https://example.com/no-summary",
+				Description: "Only content",
+				PubDate:     "2024-03-05T10:00:00Z",
+			},
+		},
+		{
+			name: "XHTML content keeps its markup",
+			input: `<feed xmlns="http://www.w3.org/2005/Atom"><entry>
+				<title>XHTML</title>
+				<link href="https://example.com/xhtml"/>
+				<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Some <b>bold</b> text</p></div></content>
+				<updated>2024-03-05T10:00:00Z</updated>
+			</entry></feed>`,
+			expected: RSSItem{
+				Title:       "XHTML",
+				Link:        "https://example.com/xhtml",
+				Description: `<div xmlns="http://www.w3.org/1999/xhtml"><p>Some <b>bold</b> text</p></div>`,
+				PubDate:     "2024-03-05T10:00:00Z",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			feed, err := parseFeed([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(feed.Channel.Item) != 1 {
+				t.Fatalf("%s: Item count mismatch: got %d, want 1", tc.name, len(feed.Channel.Item))
+			}
+
+			if got := feed.Channel.Item[0]; got != tc.expected {
+				t.Errorf("%s: Item mismatch:\n got  %+v\n want %+v", tc.name, got, tc.expected)
+			}
+
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestParseJSONFeed(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    string
+		expected RSSItem
+	}
+
+	tests := []testCase{
+		{
+			name: "HTML content, url and multiple authors",
+			input: `{"version": "https://jsonfeed.org/version/1.1", "items": [{
+				"id": "https://example.com/1",
+				"url": "https://example.com/1",
+				"external_url": "https://elsewhere.com/1",
+				"title": "Hello",
+				"content_html": "<p>Hi</p>",
+				"content_text": "Hi",
+				"date_published": "2024-03-04T08:30:00-05:00",
+				"authors": [{"name": "Ada"}, {"name": "Grace"}]
+			}]}`,
+			expected: RSSItem{
+				Title:       "Hello",
+				Link:        "https://example.com/1",
+				Description: "<p>Hi</p>",
+				PubDate:     "2024-03-04T08:30:00-05:00",
+				Author:      "Ada, Grace",
+			},
+		},
+		{
+			name: "Text content, external url and 1.0 author",
+			input: `{"version": "https://jsonfeed.org/version/1", "items": [{
+				"id": "2",
+				"external_url": "https://elsewhere.com/2",
+				"content_text": "Plain text",
+				"date_modified": "2024-03-05T10:00:00Z",
+				"author": {"name": "Linus"}
+			}]}`,
+			expected: RSSItem{
+				Link:        "https://elsewhere.com/2",
+				Description: "Plain text",
+				PubDate:     "2024-03-05T10:00:00Z",
+				Author:      "Linus",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			feed, err := parseFeed([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(feed.Channel.Item) != 1 {
+				t.Fatalf("%s: Item count mismatch: got %d, want 1", tc.name, len(feed.Channel.Item))
+			}
+
+			if got := feed.Channel.Item[0]; got != tc.expected {
+				t.Errorf("%s: Item mismatch:\n got  %+v\n want %+v", tc.name, got, tc.expected)
+			}
+
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestParseRDF(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    string
+		expected RSSItem
+	}
+
+	tests := []testCase{
+		{
+			name: "Dublin Core date and creator",
+			input: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+					xmlns:dc="http://purl.org/dc/elements/1.1/"
+					xmlns="http://purl.org/rss/1.0/">
+				<channel rdf:about="https://example.org/"><title>Journal</title></channel>
+				<item rdf:about="https://example.org/paper">
+					<title>A paper</title>
+					<link>https://example.org/paper</link>
+					<description>Abstract</description>
+					<dc:date>2024-03-04T08:30+01:00</dc:date>
+					<dc:creator>Jane Doe</dc:creator>
+				</item>
+			</rdf:RDF>`,
+			expected: RSSItem{
+				Title:       "A paper",
+				Link:        "https://example.org/paper",
+				Description: "Abstract",
+				PubDate:     "2024-03-04T08:30+01:00",
+				Author:      "Jane Doe",
+			},
+		},
+		{
+			name: "Date only",
+			input: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+					xmlns:dc="http://purl.org/dc/elements/1.1/"
+					xmlns="http://purl.org/rss/1.0/">
+				<item><title>Notice</title><dc:date>2024-03-05</dc:date></item>
+			</rdf:RDF>`,
+			expected: RSSItem{
+				Title:   "Notice",
+				PubDate: "2024-03-05",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			feed, err := parseFeed([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(feed.Channel.Item) != 1 {
+				t.Fatalf("%s: Item count mismatch: got %d, want 1", tc.name, len(feed.Channel.Item))
+			}
+
+			if got := feed.Channel.Item[0]; got != tc.expected {
+				t.Errorf("%s: Item mismatch:\n got  %+v\n want %+v", tc.name, got, tc.expected)
+			}
+
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestParseDate(t *testing.T) {
+	type testCase struct {
+		name      string
+		input     string
+		expectErr bool
+		expected  time.Time
+	}
+
+	want := time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
+
+	tests := []testCase{
+		{name: "RFC 1123", input: "Tue, 05 Mar 2024 14:30:00 GMT", expected: want},
+		{name: "RFC 1123Z", input: "Tue, 05 Mar 2024 14:30:00 +0000", expected: want},
+		{name: "RFC 822", input: "05 Mar 24 14:30 UT", expected: want},
+		{name: "RFC 3339", input: "2024-03-05T14:30:00Z", expected: want},
+		{name: "RFC 3339 with offset", input: "2024-03-05T16:30:00+02:00", expected: want},
+		{name: "Single digit day", input: "Tue, 5 Mar 2024 14:30:00 +0000", expected: want},
+		{name: "Timezone abbreviation", input: "Tue, 05 Mar 2024 09:30:00 EST", expected: want},
+		{name: "Colon offset", input: "Tue, 05 Mar 2024 15:30:00 +01:00", expected: want},
+		{name: "GMT with offset", input: "Tue, 05 Mar 2024 16:30:00 GMT+2", expected: want},
+		{name: "Full weekday and month", input: "Tuesday, 05 March 2024 14:30:00 GMT", expected: want},
+		{name: "Extra whitespace", input: "  Tue,  05 Mar 2024   14:30:00 GMT \n", expected: want},
+		{name: "Trailing comment", input: "Tue, 05 Mar 2024 14:30:00 +0000 (UTC)", expected: want},
+		{name: "Missing date", input: "", expectErr: true},
+		{name: "Garbage", input: "last tuesday", expectErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseDate(tc.input)
+
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("%s: error mismatch: got %v, expected error %t", tc.name, err, tc.expectErr)
+			}
+
+			if !tc.expectErr && !got.Equal(tc.expected) {
+				t.Errorf("%s: got %s, want %s", tc.name, got.UTC(), tc.expected)
+			}
+
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestResolvePublishDates(t *testing.T) {
+	feed := &RSSFeed{FetchedAt: time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC)}
+	feed.Channel.Item = []RSSItem{
+		{Title: "Good", PubDate: "Tue, 05 Mar 2024 14:30:00 +0000"},
+		{Title: "Missing"},
+		{Title: "Broken", PubDate: "not a date"},
+	}
+
+	resolvePublishDates(feed)
+
+	expectedFallback := []bool{false, true, true}
+	for i, item := range feed.Channel.Item {
+		if item.DateFallback != expectedFallback[i] {
+			t.Errorf("%s: DateFallback mismatch: got %t, want %t", item.Title, item.DateFallback, expectedFallback[i])
+		}
+
+		if item.DateFallback && !item.PublishedAt.Equal(feed.FetchedAt) {
+			t.Errorf("%s: PublishedAt mismatch: got %s, want fetch time %s", item.Title, item.PublishedAt, feed.FetchedAt)
+		}
+	}
+}
+
+
+func TestFetchFeedIfModified(t *testing.T) {
+	const etag = `"v1"`
+	const lastModified = "Tue, 05 Mar 2024 14:30:00 GMT"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte(`<rss><channel><title>Cached</title><item><title>Post</title></item></channel></rss>`))
+	}))
+	defer server.Close()
+
+	first, err := FetchFeedIfModified(context.Background(), server.URL, CacheValidators{})
+	if err != nil {
+		t.Fatalf("first fetch: unexpected error: %v", err)
+	}
+
+	if first.NotModified || len(first.Channel.Item) != 1 {
+		t.Fatalf("first fetch: got NotModified=%t with %d items, want full feed", first.NotModified, len(first.Channel.Item))
+	}
+
+	if first.Cache.ETag != etag || first.Cache.LastModified != lastModified {
+		t.Errorf("first fetch: Cache mismatch: got %+v", first.Cache)
+	}
+
+	second, err := FetchFeedIfModified(context.Background(), server.URL, first.Cache)
+	if err != nil {
+		t.Fatalf("second fetch: unexpected error: %v", err)
+	}
+
+	if !second.NotModified || len(second.Channel.Item) != 0 {
+		t.Errorf("second fetch: got NotModified=%t with %d items, want 304", second.NotModified, len(second.Channel.Item))
+	}
+
+	if second.Cache != first.Cache {
+		t.Errorf("second fetch: Cache mismatch: got %+v, want %+v", second.Cache, first.Cache)
+	}
+}
+
+
+func TestFetchFeedErrors(t *testing.T) {
+	type testCase struct {
+		name       string
+		mockStatus int
+		retryAfter string
+		mockBody   string
+		check      func(err error) bool
+	}
+
+	tests := []testCase{
+		{
+			name:       "404 Not Found",
+			mockStatus: http.StatusNotFound,
+			mockBody:   `<html>Not Found</html>`,
+			check: func(err error) bool {
+				var target *NotFoundError
+				return errors.As(err, &target)
+			},
+		},
+		{
+			name:       "410 Gone",
+			mockStatus: http.StatusGone,
+			check: func(err error) bool {
+				var target *GoneError
+				return errors.As(err, &target)
+			},
+		},
+		{
+			name:       "429 with Retry-After seconds",
+			mockStatus: http.StatusTooManyRequests,
+			retryAfter: "120",
+			check: func(err error) bool {
+				var target *RateLimitedError
+				return errors.As(err, &target) && target.RetryAfter == 2*time.Minute
+			},
+		},
+		{
+			name:       "503 with Retry-After date",
+			mockStatus: http.StatusServiceUnavailable,
+			retryAfter: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+			check: func(err error) bool {
+				var target *ServerError
+				return errors.As(err, &target) && target.StatusCode == 503 &&
+					target.RetryAfter > 59*time.Minute && target.RetryAfter <= time.Hour
+			},
+		},
+		{
+			name:       "403 Forbidden",
+			mockStatus: http.StatusForbidden,
+			check: func(err error) bool {
+				var target *HTTPError
+				return errors.As(err, &target) && target.StatusCode == 403
+			},
+		},
+		{
+			name:       "200 with HTML body",
+			mockStatus: http.StatusOK,
+			mockBody:   `<html><body>Not a feed`,
+			check: func(err error) bool {
+				var target *ParseError
+				return errors.As(err, &target)
+			},
+		},
+		{
+			name:       "200 with well-formed HTML body",
+			mockStatus: http.StatusOK,
+			mockBody:   `<html><body><p>Not a feed</p></body></html>`,
+			check: func(err error) bool {
+				var target *ParseError
+				return errors.As(err, &target)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.retryAfter != "" {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				w.WriteHeader(tc.mockStatus)
+				w.Write([]byte(tc.mockBody))
+			}))
+			defer server.Close()
+
+			_, err := FetchFeed(context.Background(), server.URL)
+			if err == nil {
+				t.Fatalf("%s: expected an error", tc.name)
+			}
+
+			if !tc.check(err) {
+				t.Errorf("%s: unexpected error type %T: %v", tc.name, err, err)
+			}
+
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+}
+
+
+func TestParseHints(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    string
+		expected ScheduleHints
+	}
+
+	tests := []testCase{
+		{
+			name: "RSS 2.0 ttl, skipHours and skipDays",
+			input: `<rss><channel>
+				<ttl>60</ttl>
+				<skipHours><hour>0</hour><hour>24</hour><hour>3</hour></skipHours>
+				<skipDays><day>Saturday</day><day>Sunday</day></skipDays>
+			</channel></rss>`,
+			expected: ScheduleHints{
+				TTL:       time.Hour,
+				SkipHours: []int{0, 0, 3},
+				SkipDays:  []time.Weekday{time.Saturday, time.Sunday},
+			},
+		},
+		{
+			name: "RDF syndication module",
+			input: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+					xmlns:sy="http://purl.org/rss/1.0/modules/syndication/"
+					xmlns="http://purl.org/rss/1.0/">
+				<channel>
+					<sy:updatePeriod>daily</sy:updatePeriod>
+					<sy:updateFrequency>4</sy:updateFrequency>
+				</channel>
+			</rdf:RDF>`,
+			expected: ScheduleHints{UpdatePeriod: 6 * time.Hour},
+		},
+		{
+			name:     "No hints",
+			input:    `<rss><channel><ttl>soon</ttl></channel></rss>`,
+			expected: ScheduleHints{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			feed, err := parseFeed([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := feed.Hints
+			if got.TTL != tc.expected.TTL || got.UpdatePeriod != tc.expected.UpdatePeriod {
+				t.Errorf("%s: TTL/UpdatePeriod mismatch: got %s/%s, want %s/%s",
+					tc.name, got.TTL, got.UpdatePeriod, tc.expected.TTL, tc.expected.UpdatePeriod)
+			}
+
+			if fmt.Sprint(got.SkipHours) != fmt.Sprint(tc.expected.SkipHours) {
+				t.Errorf("%s: SkipHours mismatch: got %v, want %v", tc.name, got.SkipHours, tc.expected.SkipHours)
+			}
+
+			if fmt.Sprint(got.SkipDays) != fmt.Sprint(tc.expected.SkipDays) {
+				t.Errorf("%s: SkipDays mismatch: got %v, want %v", tc.name, got.SkipDays, tc.expected.SkipDays)
+			}
+
+			fmt.Printf("✅ Test Passed: %s\n", tc.name)
+		})
+	}
+
+	skipping := ScheduleHints{SkipHours: []int{3}, SkipDays: []time.Weekday{time.Sunday}}
+	if !skipping.Skips(time.Date(2024, time.March, 5, 3, 15, 0, 0, time.UTC)) {
+		t.Errorf("Skips: expected 03:15 GMT to be skipped")
+	}
+	if !skipping.Skips(time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Skips: expected a Sunday to be skipped")
+	}
+	if skipping.Skips(time.Date(2024, time.March, 5, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("Skips: expected 04:00 GMT on a Tuesday not to be skipped")
+	}
+}
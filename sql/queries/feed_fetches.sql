@@ -0,0 +1,13 @@
+-- name: CreateFeedFetch :exec
+INSERT INTO feed_fetches (id, feed_id, started_at, duration_ms, http_status, bytes, items_seen, posts_inserted, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: GetFeedFetches :many
+SELECT * FROM feed_fetches
+WHERE feed_id = $1
+ORDER BY started_at DESC
+LIMIT $2;
+
+-- name: PruneFeedFetches :execrows
+DELETE FROM feed_fetches
+WHERE started_at < $1;
@@ -16,7 +16,12 @@ INNER JOIN feeds ON insert_feed_follows.feed_id = feeds.id;
 SELECT 
     feed_follows.*,
     feeds.name AS feed_name,
-    users.name AS user_name
+    users.name AS user_name,
+    (
+        SELECT COUNT(*) FROM posts
+        LEFT JOIN post_reads ON posts.id = post_reads.post_id AND post_reads.user_id = feed_follows.user_id
+        WHERE posts.feed_id = feed_follows.feed_id AND post_reads.post_id IS NULL
+    ) AS unread_count
 FROM feed_follows
 INNER JOIN users ON feed_follows.user_id = users.id
 INNER JOIN feeds ON feed_follows.feed_id = feeds.id
@@ -25,4 +30,10 @@ WHERE feed_follows.user_id = $1;
 
 -- name: UnfollowFeed :exec
 DELETE FROM feed_follows
-WHERE user_id = $1 AND feed_id = $2;
\ No newline at end of file
+WHERE user_id = $1 AND feed_id = $2;
+
+-- name: GetFollowedFeeds :many
+SELECT feeds.* FROM feeds
+INNER JOIN feed_follows ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1
+ORDER BY feeds.name;
@@ -13,10 +13,81 @@ WHERE feeds.url = $1;
 
 -- name: MarkFetched :exec
 UPDATE feeds
-SET last_fetched_at = NOW(), updated_at = NOW()
+SET last_fetched_at = NOW(), updated_at = NOW(),
+    etag = sqlc.arg(etag), last_modified = sqlc.arg(last_modified),
+    poll_interval_seconds = sqlc.arg(poll_interval_seconds),
+    last_error = NULL, consecutive_failures = 0,
+    next_fetch_at = NOW() + (sqlc.arg(next_fetch_in_seconds)::int * INTERVAL '1 second')
+WHERE feeds.id = sqlc.arg(id);
+
+-- name: ClaimFeedsToFetch :many
+UPDATE feeds
+SET claimed_until = NOW() + (sqlc.arg(lease_seconds)::int * INTERVAL '1 second')
+WHERE id IN (
+    SELECT id FROM feeds
+    WHERE disabled_at IS NULL
+        AND (claimed_until IS NULL OR claimed_until < NOW())
+        AND (next_fetch_at IS NULL OR next_fetch_at <= NOW())
+    ORDER BY next_fetch_at ASC NULLS FIRST
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ReleaseFeedClaim :exec
+-- only release the lease this claim took: once it expires another
+-- process may have claimed the feed again
+UPDATE feeds
+SET claimed_until = NULL
+WHERE feeds.id = sqlc.arg(id) AND claimed_until = sqlc.arg(claimed_until);
+
+-- name: DisableFeed :exec
+UPDATE feeds
+SET disabled_at = NOW(), updated_at = NOW()
 WHERE feeds.id = $1;
 
--- name: GetNextFeedToFetch :one
+-- name: RecordFeedFailure :one
+UPDATE feeds
+SET last_error = sqlc.arg(last_error),
+    consecutive_failures = consecutive_failures + 1,
+    disabled_at = CASE
+        WHEN consecutive_failures + 1 >= sqlc.arg(max_failures)::int THEN NOW()
+        ELSE disabled_at
+    END,
+    next_fetch_at = NOW() + (sqlc.arg(retry_in_seconds)::int * INTERVAL '1 second'),
+    updated_at = NOW()
+WHERE feeds.id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListBrokenFeeds :many
 SELECT * FROM feeds
-ORDER BY last_fetched_at ASC NULLS FIRST 
-LIMIT 1;
+WHERE disabled_at IS NOT NULL OR consecutive_failures > 0
+ORDER BY disabled_at DESC NULLS LAST, consecutive_failures DESC;
+
+-- name: EnableFeed :execrows
+UPDATE feeds
+SET disabled_at = NULL, last_error = NULL, consecutive_failures = 0, next_fetch_at = NULL, updated_at = NOW()
+WHERE feeds.url = $1;
+
+-- name: ScheduleFeed :exec
+UPDATE feeds
+SET next_fetch_at = NOW() + (sqlc.arg(retry_in_seconds)::int * INTERVAL '1 second'), updated_at = NOW()
+WHERE feeds.id = sqlc.arg(id);
+
+-- name: GetFeedsByName :many
+SELECT * FROM feeds
+WHERE feeds.name = $1;
+
+-- name: ClaimFeed :one
+UPDATE feeds
+SET claimed_until = NOW() + (sqlc.arg(lease_seconds)::int * INTERVAL '1 second')
+WHERE feeds.id = sqlc.arg(id)
+    AND (claimed_until IS NULL OR claimed_until < NOW())
+RETURNING *;
+
+-- name: CountDueFeeds :one
+SELECT
+    COUNT(*) FILTER (WHERE next_fetch_at IS NULL OR next_fetch_at <= NOW()) AS due,
+    COUNT(*) FILTER (WHERE next_fetch_at < NOW() - (sqlc.arg(overdue_seconds)::int * INTERVAL '1 second')) AS overdue
+FROM feeds
+WHERE disabled_at IS NULL;
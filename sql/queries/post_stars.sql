@@ -0,0 +1,15 @@
+-- name: StarPost :exec
+INSERT INTO post_stars (user_id, post_id, starred_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnstarPost :execrows
+DELETE FROM post_stars
+WHERE user_id = $1 AND post_id = $2;
+
+-- name: GetStarredPosts :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, feeds.name AS feed_name, post_stars.starred_at FROM post_stars
+JOIN posts ON post_stars.post_id = posts.id
+JOIN feeds ON posts.feed_id = feeds.id
+WHERE post_stars.user_id = $1
+ORDER BY post_stars.starred_at DESC;
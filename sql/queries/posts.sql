@@ -1,11 +1,33 @@
 -- name: CreatePost :one
+-- posts queries list their columns to leave the search vector behind
 INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 ON CONFLICT (url) DO NOTHING
-RETURNING *;
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id;
+
+-- name: GetPost :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id FROM posts
+WHERE id = $1;
 
 -- name: GetPosts :many
-SELECT posts.* FROM posts
-JOIN feeds ON posts.feed_id = feeds.id
-WHERE feeds.user_id = $1
-ORDER BY published_at DESC LIMIT $2;
\ No newline at end of file
+-- posts of the feeds the user follows, newest first, with when the user
+-- read them. Every filter is optional, before_published_at and before_id
+-- continue after a cursor
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, post_reads.read_at FROM posts
+JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+LEFT JOIN post_reads ON posts.id = post_reads.post_id AND post_reads.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(feed_id)::uuid IS NULL OR posts.feed_id = sqlc.narg(feed_id))
+    AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since))
+    AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until))
+    AND (sqlc.narg(before_published_at)::timestamp IS NULL
+        OR (posts.published_at, posts.id) < (sqlc.narg(before_published_at), sqlc.narg(before_id)::uuid))
+    AND (NOT sqlc.arg(unread_only)::boolean OR post_reads.read_at IS NULL)
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: GetRecentPublishDates :many
+SELECT published_at FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC
+LIMIT $2;
@@ -0,0 +1,24 @@
+-- name: SearchPosts :many
+-- query is a tsquery built by internal/search. The best matches among the
+-- feeds the user follows come back with their matched words highlighted,
+-- headlines are only built for the page being returned
+WITH matches AS (
+    SELECT posts.id, posts.title, posts.url, posts.description, posts.published_at,
+        feeds.name AS feed_name,
+        ts_rank(posts.search, query) AS rank,
+        query
+    FROM posts
+    JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+    JOIN feeds ON posts.feed_id = feeds.id
+    CROSS JOIN to_tsquery('english', sqlc.arg(query)) AS query
+    WHERE feed_follows.user_id = sqlc.arg(user_id)
+        AND (sqlc.narg(feed_id)::uuid IS NULL OR posts.feed_id = sqlc.narg(feed_id))
+        AND posts.search @@ query
+    ORDER BY rank DESC, posts.published_at DESC
+    LIMIT sqlc.arg(page_size)
+)
+SELECT id, title, url, published_at, feed_name, rank,
+    ts_headline('english', title, query, 'StartSel=**, StopSel=**, HighlightAll=true')::text AS title_highlight,
+    ts_headline('english', regexp_replace(coalesce(description, ''), '<[^>]*>', ' ', 'g'), query, 'StartSel=**, StopSel=**, MaxWords=30, MinWords=15')::text AS snippet
+FROM matches
+ORDER BY rank DESC, published_at DESC;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD disabled_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP disabled_at;
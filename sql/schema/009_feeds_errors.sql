@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD last_error TEXT,
+ADD consecutive_failures INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP last_error,
+DROP consecutive_failures;
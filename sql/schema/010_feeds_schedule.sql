@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD next_fetch_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP next_fetch_at;
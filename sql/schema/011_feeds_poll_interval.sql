@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD poll_interval_seconds INTEGER;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP poll_interval_seconds;
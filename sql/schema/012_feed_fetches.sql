@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE feed_fetches (
+    id UUID PRIMARY KEY,
+    feed_id UUID NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    http_status INTEGER,
+    bytes INTEGER,
+    items_seen INTEGER NOT NULL,
+    posts_inserted INTEGER NOT NULL,
+    error TEXT,
+    CONSTRAINT fk_feed_id
+        FOREIGN KEY (feed_id) REFERENCES feeds(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX feed_fetches_feed_id_started_at ON feed_fetches (feed_id, started_at);
+
+-- +goose Down
+DROP TABLE feed_fetches;
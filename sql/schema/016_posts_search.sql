@@ -0,0 +1,13 @@
+-- +goose Up
+-- titles weigh more than descriptions, whose HTML tags aren't words
+ALTER TABLE posts ADD COLUMN search TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('english', regexp_replace(coalesce(description, ''), '<[^>]*>', ' ', 'g')), 'B')
+    ) STORED;
+
+CREATE INDEX posts_search ON posts USING GIN (search);
+
+-- +goose Down
+DROP INDEX posts_search;
+ALTER TABLE posts DROP COLUMN search;
@@ -0,0 +1,8 @@
+// Package schema embeds the goose migrations so the binary can apply them
+// itself with gator migrate
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
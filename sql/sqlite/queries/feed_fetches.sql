@@ -0,0 +1,13 @@
+-- name: CreateFeedFetch :exec
+INSERT INTO feed_fetches (id, feed_id, started_at, duration_ms, http_status, bytes, items_seen, posts_inserted, error)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetFeedFetches :many
+SELECT * FROM feed_fetches
+WHERE feed_id = ?
+ORDER BY julianday(started_at) DESC
+LIMIT ?;
+
+-- name: PruneFeedFetches :execrows
+DELETE FROM feed_fetches
+WHERE julianday(started_at) < julianday(?);
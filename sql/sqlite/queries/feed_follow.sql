@@ -0,0 +1,33 @@
+-- name: CreateFeedFollow :one
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *,
+    (SELECT feeds.name FROM feeds WHERE feeds.id = feed_id) AS feed_name,
+    (SELECT users.name FROM users WHERE users.id = user_id) AS user_name;
+
+
+-- name: GetFeedFollowsForUser :many
+SELECT
+    feed_follows.*,
+    feeds.name AS feed_name,
+    users.name AS user_name,
+    (
+        SELECT COUNT(*) FROM posts
+        LEFT JOIN post_reads ON posts.id = post_reads.post_id AND post_reads.user_id = feed_follows.user_id
+        WHERE posts.feed_id = feed_follows.feed_id AND post_reads.post_id IS NULL
+    ) AS unread_count
+FROM feed_follows
+INNER JOIN users ON feed_follows.user_id = users.id
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = ?;
+
+
+-- name: UnfollowFeed :exec
+DELETE FROM feed_follows
+WHERE user_id = ? AND feed_id = ?;
+
+-- name: GetFollowedFeeds :many
+SELECT feeds.* FROM feeds
+INNER JOIN feed_follows ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = ?
+ORDER BY feeds.name;
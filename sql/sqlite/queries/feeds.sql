@@ -0,0 +1,94 @@
+-- name: CreateFeed :one
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListFeeds :many
+SELECT feeds.name, feeds.url, users.name AS user_name FROM feeds
+JOIN users ON feeds.user_id = users.id;
+
+-- name: GetFeed :one
+SELECT * FROM feeds
+WHERE feeds.url = ?;
+
+-- name: MarkFetched :exec
+UPDATE feeds
+SET last_fetched_at = datetime('now'), updated_at = datetime('now'),
+    etag = sqlc.arg(etag), last_modified = sqlc.arg(last_modified),
+    poll_interval_seconds = sqlc.arg(poll_interval_seconds),
+    last_error = NULL, consecutive_failures = 0,
+    next_fetch_at = datetime('now', CAST(sqlc.arg(next_fetch_in_seconds) AS INTEGER) || ' seconds')
+WHERE feeds.id = sqlc.arg(id);
+
+-- name: ClaimFeedsToFetch :many
+-- SQLite has a single writer, so the UPDATE claims its batch atomically
+-- without FOR UPDATE SKIP LOCKED
+UPDATE feeds
+SET claimed_until = datetime('now', CAST(sqlc.arg(lease_seconds) AS INTEGER) || ' seconds')
+WHERE id IN (
+    SELECT id FROM feeds
+    WHERE disabled_at IS NULL
+        AND (claimed_until IS NULL OR claimed_until < datetime('now'))
+        AND (next_fetch_at IS NULL OR next_fetch_at <= datetime('now'))
+    ORDER BY next_fetch_at ASC NULLS FIRST
+    LIMIT sqlc.arg(batch_size)
+)
+RETURNING *;
+
+-- name: ReleaseFeedClaim :exec
+-- only release the lease this claim took: once it expires another
+-- process may have claimed the feed again
+UPDATE feeds
+SET claimed_until = NULL
+WHERE feeds.id = sqlc.arg(id) AND julianday(claimed_until) = julianday(sqlc.arg(claimed_until));
+
+-- name: DisableFeed :exec
+UPDATE feeds
+SET disabled_at = datetime('now'), updated_at = datetime('now')
+WHERE feeds.id = ?;
+
+-- name: RecordFeedFailure :one
+UPDATE feeds
+SET last_error = sqlc.arg(last_error),
+    consecutive_failures = consecutive_failures + 1,
+    disabled_at = CASE
+        WHEN consecutive_failures + 1 >= CAST(sqlc.arg(max_failures) AS INTEGER) THEN datetime('now')
+        ELSE disabled_at
+    END,
+    next_fetch_at = datetime('now', CAST(sqlc.arg(retry_in_seconds) AS INTEGER) || ' seconds'),
+    updated_at = datetime('now')
+WHERE feeds.id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListBrokenFeeds :many
+SELECT * FROM feeds
+WHERE disabled_at IS NOT NULL OR consecutive_failures > 0
+ORDER BY disabled_at DESC NULLS LAST, consecutive_failures DESC;
+
+-- name: EnableFeed :execrows
+UPDATE feeds
+SET disabled_at = NULL, last_error = NULL, consecutive_failures = 0, next_fetch_at = NULL, updated_at = datetime('now')
+WHERE feeds.url = ?;
+
+-- name: ScheduleFeed :exec
+UPDATE feeds
+SET next_fetch_at = datetime('now', CAST(sqlc.arg(retry_in_seconds) AS INTEGER) || ' seconds'), updated_at = datetime('now')
+WHERE feeds.id = sqlc.arg(id);
+
+-- name: GetFeedsByName :many
+SELECT * FROM feeds
+WHERE feeds.name = ?;
+
+-- name: ClaimFeed :one
+UPDATE feeds
+SET claimed_until = datetime('now', CAST(sqlc.arg(lease_seconds) AS INTEGER) || ' seconds')
+WHERE feeds.id = sqlc.arg(id)
+    AND (claimed_until IS NULL OR claimed_until < datetime('now'))
+RETURNING *;
+
+-- name: CountDueFeeds :one
+SELECT
+    COUNT(*) FILTER (WHERE next_fetch_at IS NULL OR next_fetch_at <= datetime('now')) AS due,
+    COUNT(*) FILTER (WHERE next_fetch_at < datetime('now', -CAST(sqlc.arg(overdue_seconds) AS INTEGER) || ' seconds')) AS overdue
+FROM feeds
+WHERE disabled_at IS NULL;
@@ -0,0 +1,19 @@
+-- name: MarkPostRead :exec
+INSERT INTO post_reads (user_id, post_id, read_at)
+VALUES (?, ?, ?)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostUnread :execrows
+DELETE FROM post_reads
+WHERE user_id = ? AND post_id = ?;
+
+-- name: MarkPostsRead :execrows
+-- marks every post of the feeds the user follows as read, or only the
+-- posts of feed_id when it is set
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT feed_follows.user_id, posts.id, sqlc.arg(read_at)
+FROM posts
+JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(feed_id) IS NULL OR posts.feed_id = sqlc.narg(feed_id))
+ON CONFLICT (user_id, post_id) DO NOTHING;
@@ -0,0 +1,15 @@
+-- name: StarPost :exec
+INSERT INTO post_stars (user_id, post_id, starred_at)
+VALUES (?, ?, ?)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnstarPost :execrows
+DELETE FROM post_stars
+WHERE user_id = ? AND post_id = ?;
+
+-- name: GetStarredPosts :many
+SELECT posts.*, feeds.name AS feed_name, post_stars.starred_at FROM post_stars
+JOIN posts ON post_stars.post_id = posts.id
+JOIN feeds ON posts.feed_id = feeds.id
+WHERE post_stars.user_id = ?
+ORDER BY julianday(post_stars.starred_at) DESC;
@@ -0,0 +1,31 @@
+-- name: CreatePost :one
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (url) DO NOTHING
+RETURNING *;
+
+-- name: GetPost :one
+SELECT * FROM posts
+WHERE id = ?;
+
+-- name: GetPosts :many
+-- published_at keeps the feed's own zone offset, so compare and order by
+-- the instant rather than the string
+SELECT posts.*, post_reads.read_at FROM posts
+JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+LEFT JOIN post_reads ON posts.id = post_reads.post_id AND post_reads.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(feed_id) IS NULL OR posts.feed_id = sqlc.narg(feed_id))
+    AND (sqlc.narg(since) IS NULL OR julianday(posts.published_at) >= julianday(sqlc.narg(since)))
+    AND (sqlc.narg(until) IS NULL OR julianday(posts.published_at) < julianday(sqlc.narg(until)))
+    AND (sqlc.narg(before_published_at) IS NULL
+        OR (julianday(posts.published_at), posts.id) < (julianday(sqlc.narg(before_published_at)), sqlc.narg(before_id)))
+    AND (NOT sqlc.arg(unread_only) OR post_reads.read_at IS NULL)
+ORDER BY julianday(posts.published_at) DESC, posts.id DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: GetRecentPublishDates :many
+SELECT published_at FROM posts
+WHERE feed_id = ?
+ORDER BY julianday(published_at) DESC
+LIMIT ?;
@@ -0,0 +1,79 @@
+-- +goose Up
+CREATE TABLE users (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name TEXT UNIQUE
+);
+
+CREATE TABLE feeds (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name TEXT NOT NULL,
+    url TEXT UNIQUE,
+    user_id UUID NOT NULL,
+    last_fetched_at TIMESTAMP,
+    claimed_until TIMESTAMP,
+    etag TEXT,
+    last_modified TEXT,
+    disabled_at TIMESTAMP,
+    last_error TEXT,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    next_fetch_at TIMESTAMP,
+    poll_interval_seconds INTEGER,
+    CONSTRAINT fk_user_id
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE feed_follows (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    feed_id UUID NOT NULL,
+    CONSTRAINT unique_user_feed UNIQUE (user_id, feed_id),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_feed
+        FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+CREATE TABLE posts (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL UNIQUE,
+    description TEXT,
+    published_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL,
+    CONSTRAINT fk_feed_id
+        FOREIGN KEY (feed_id) REFERENCES feeds(id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE feed_fetches (
+    id UUID PRIMARY KEY,
+    feed_id UUID NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    http_status INTEGER,
+    bytes INTEGER,
+    items_seen INTEGER NOT NULL,
+    posts_inserted INTEGER NOT NULL,
+    error TEXT,
+    CONSTRAINT fk_feed_id
+        FOREIGN KEY (feed_id) REFERENCES feeds(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX feed_fetches_feed_id_started_at ON feed_fetches (feed_id, started_at);
+
+-- +goose Down
+DROP TABLE feed_fetches;
+DROP TABLE posts;
+DROP TABLE feed_follows;
+DROP TABLE feeds;
+DROP TABLE users;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX posts_feed_id ON posts (feed_id);
+
+-- +goose Down
+DROP INDEX posts_feed_id;
@@ -0,0 +1,8 @@
+// Package schema embeds the SQLite migrations, the SQLite counterpart of
+// sql/schema
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS